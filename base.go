@@ -13,7 +13,8 @@ var ValidationError = Template{
 		}
 		return fmt.Sprintf("%s validation error", resource)
 	},
-	Params: Params{},
+	Params:         Params{},
+	StackTraceMode: StackTraceOff,
 }
 
 const CodeBlockingLink Code = "BlockingLink"
@@ -67,6 +68,35 @@ var NotFound = Template{
 		}
 		return fmt.Sprintf("%s not found", resource)
 	},
+	Params:         Params{},
+	StackTraceMode: StackTraceOff,
+}
+
+const CodeMethodNotAllowed Code = "MethodNotAllowed"
+
+var MethodNotAllowed = Template{
+	Code: CodeMethodNotAllowed,
+	Message: func(params map[string]any) string {
+		method, found := params[keyMethod]
+		if !found {
+			return "Method not allowed"
+		}
+		return fmt.Sprintf("Method %s not allowed", method)
+	},
+	Params: Params{},
+}
+
+const CodePayloadTooLarge Code = "PayloadTooLarge"
+
+var PayloadTooLarge = Template{
+	Code: CodePayloadTooLarge,
+	Message: func(params map[string]any) string {
+		limit, found := params[keyLimit]
+		if !found {
+			return "Payload too large"
+		}
+		return fmt.Sprintf("Payload exceeds %v bytes", limit)
+	},
 	Params: Params{},
 }
 
@@ -133,7 +163,7 @@ var PreconditionFailed = Template{
 const CodePreconditionRequired Code = "PreconditionRequired"
 
 var PreconditionRequired = Template{
-	Code: CodePreconditionFailed,
+	Code: CodePreconditionRequired,
 	Message: func(params map[string]any) string {
 		precondition, found := params[keyPrecondition]
 		if !found {
@@ -158,7 +188,8 @@ var InternalError = Template{
 	Message: func(params map[string]any) string {
 		return "Internal error"
 	},
-	Params: Params{},
+	Params:         Params{},
+	StackTraceMode: StackTraceFull,
 }
 
 const CodeNotImplemented Code = "NotImplemented"
@@ -170,3 +201,43 @@ var NotImplemented = Template{
 	},
 	Params: Params{},
 }
+
+const CodeBadGateway Code = "BadGateway"
+
+var BadGateway = Template{
+	Code: CodeBadGateway,
+	Message: func(params map[string]any) string {
+		return "Bad gateway"
+	},
+	Params: Params{},
+}
+
+const CodeGatewayTimeout Code = "GatewayTimeout"
+
+var GatewayTimeout = Template{
+	Code: CodeGatewayTimeout,
+	Message: func(params map[string]any) string {
+		return "Gateway timeout"
+	},
+	Params: Params{},
+}
+
+const CodeUnavailable Code = "Unavailable"
+
+var Unavailable = Template{
+	Code: CodeUnavailable,
+	Message: func(params map[string]any) string {
+		return "Service unavailable"
+	},
+	Params: Params{},
+}
+
+const CodeMultiError Code = "MultiError"
+
+var MultiError = Template{
+	Code: CodeMultiError,
+	Message: func(params map[string]any) string {
+		return "Multiple errors occurred"
+	},
+	Params: Params{},
+}
@@ -0,0 +1,151 @@
+package errors
+
+import "reflect"
+
+func Walk(err error, visit func(err error, depth int) bool) {
+	visited := map[error]struct{}{}
+	walk(err, 0, visited, visit)
+}
+
+func walk(err error, depth int, visited map[error]struct{}, visit func(err error, depth int) bool) bool {
+	if err == nil {
+		return true
+	}
+	if isComparable(err) {
+		_, found := visited[err]
+		if found {
+			return true
+		}
+		visited[err] = struct{}{}
+	}
+	if !visit(err, depth) {
+		return false
+	}
+	for _, cause := range unwrap(err) {
+		if !walk(cause, depth+1, visited, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+func unwrap(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		cause := e.Unwrap()
+		if cause == nil {
+			return nil
+		}
+		return []error{cause}
+	case interface{ Cause() error }:
+		cause := e.Cause()
+		if cause == nil {
+			return nil
+		}
+		return []error{cause}
+	default:
+		return nil
+	}
+}
+
+func isComparable(err error) bool {
+	return reflect.ValueOf(err).Comparable()
+}
+
+func Root(err error) error {
+	visited := map[error]struct{}{}
+	for err != nil {
+		if isComparable(err) {
+			_, found := visited[err]
+			if found {
+				return err
+			}
+			visited[err] = struct{}{}
+		}
+		causes := unwrap(err)
+		if len(causes) == 0 || causes[0] == nil {
+			return err
+		}
+		err = causes[0]
+	}
+	return nil
+}
+
+func Codes(err error) []Code {
+	var codes []Code
+	Walk(err, func(err error, _ int) bool {
+		e, ok := err.(*Error)
+		if ok {
+			codes = append(codes, e.Code())
+		}
+		return true
+	})
+	return codes
+}
+
+func Find(err error, template Template) (*Error, bool) {
+	return FindFunc(err, matchTemplate(template))
+}
+
+func FindFunc(err error, predicate func(e *Error) bool) (*Error, bool) {
+	var found *Error
+	Walk(err, func(err error, _ int) bool {
+		e, ok := err.(*Error)
+		if ok && predicate(e) {
+			found = e
+			return false
+		}
+		return true
+	})
+	return found, found != nil
+}
+
+func FindDeepest(err error, template Template) (*Error, bool) {
+	return FindDeepestFunc(err, matchTemplate(template))
+}
+
+func FindDeepestFunc(err error, predicate func(e *Error) bool) (*Error, bool) {
+	var found *Error
+	foundDepth := -1
+	Walk(err, func(err error, depth int) bool {
+		e, ok := err.(*Error)
+		if ok && depth > foundDepth && predicate(e) {
+			found = e
+			foundDepth = depth
+		}
+		return true
+	})
+	return found, found != nil
+}
+
+func matchTemplate(template Template) func(e *Error) bool {
+	return func(e *Error) bool {
+		return e.hasCode(template.Code)
+	}
+}
+
+func HasCycle(err error) bool {
+	return hasCycle(err, map[error]struct{}{})
+}
+
+func hasCycle(err error, path map[error]struct{}) bool {
+	if err == nil {
+		return false
+	}
+	if isComparable(err) {
+		_, found := path[err]
+		if found {
+			return true
+		}
+		path[err] = struct{}{}
+		defer delete(path, err)
+	}
+	for _, cause := range unwrap(err) {
+		if hasCycle(cause, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package main
+
+import "debug/dwarf"
+
+type inliner struct {
+	data *dwarf.Data
+}
+
+func (in *inliner) function(pc uint64) (string, error) {
+	reader := in.data.Reader()
+	_, err := reader.SeekPC(pc)
+	if err != nil {
+		return "", err
+	}
+	for {
+		entry, err := reader.Next()
+		if err != nil {
+			return "", err
+		}
+		if entry == nil || entry.Tag == 0 {
+			return "", nil
+		}
+		if entry.Tag != dwarf.TagSubprogram {
+			if entry.Children {
+				reader.SkipChildren()
+			}
+			continue
+		}
+		contains, err := in.contains(entry, pc)
+		if err != nil {
+			return "", err
+		}
+		if !contains {
+			if entry.Children {
+				reader.SkipChildren()
+			}
+			continue
+		}
+		if !entry.Children {
+			return "", nil
+		}
+		return in.innermost(reader, pc)
+	}
+}
+
+func (in *inliner) innermost(reader *dwarf.Reader, pc uint64) (string, error) {
+	function := ""
+	depth := 1
+	for depth != 0 {
+		entry, err := reader.Next()
+		if err != nil {
+			return "", err
+		}
+		if entry == nil {
+			break
+		}
+		if entry.Tag == 0 {
+			depth--
+			continue
+		}
+		if entry.Tag == dwarf.TagInlinedSubroutine {
+			contains, err := in.contains(entry, pc)
+			if err != nil {
+				return "", err
+			}
+			if !contains {
+				if entry.Children {
+					reader.SkipChildren()
+				}
+				continue
+			}
+			function, err = in.name(entry)
+			if err != nil {
+				return "", err
+			}
+		}
+		if entry.Children {
+			depth++
+		}
+	}
+	return function, nil
+}
+
+func (in *inliner) contains(entry *dwarf.Entry, pc uint64) (bool, error) {
+	ranges, err := in.data.Ranges(entry)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range ranges {
+		if pc >= r[0] && pc < r[1] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (in *inliner) name(entry *dwarf.Entry) (string, error) {
+	origin, ok := entry.Val(dwarf.AttrAbstractOrigin).(dwarf.Offset)
+	if !ok {
+		return "", nil
+	}
+	reader := in.data.Reader()
+	reader.Seek(origin)
+	originEntry, err := reader.Next()
+	if err != nil || originEntry == nil {
+		return "", err
+	}
+	name, _ := originEntry.Val(dwarf.AttrName).(string)
+	return name, nil
+}
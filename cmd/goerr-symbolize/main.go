@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/gosym"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+func main() {
+	binary := flag.String("binary", "", "path to the binary that produced the payload")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -binary <path> [payload]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *binary == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	err := run(*binary, flag.Arg(0), os.Stdout)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(binaryPath string, payloadPath string, out io.Writer) error {
+	payload, err := readPayload(payloadPath)
+	if err != nil {
+		return err
+	}
+	symbolizer, err := newSymbolizer(binaryPath)
+	if err != nil {
+		return err
+	}
+	e, err := decode(payload)
+	if err != nil {
+		return err
+	}
+	errors.Walk(e, func(err error, depth int) bool {
+		if depth != 0 {
+			_, _ = fmt.Fprint(out, "\ncaused by: ")
+		}
+		ge, ok := err.(*errors.Error)
+		if !ok {
+			_, _ = fmt.Fprintln(out, err.Error())
+			return true
+		}
+		_, _ = fmt.Fprintf(out, "%s: %s\n", ge.Code(), ge.Error())
+		st := ge.RemoteStackTrace()
+		raw := st.Raw()
+		if raw != nil {
+			symbolizer.write(out, raw)
+		} else if st != nil {
+			_, _ = fmt.Fprintln(out, st.String())
+		}
+		return true
+	})
+	return nil
+}
+
+func readPayload(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func decode(payload []byte) (*errors.Error, error) {
+	e := &errors.Error{}
+	payload = bytes.TrimSpace(payload)
+	if bytes.HasPrefix(payload, []byte("<")) {
+		err := xml.Unmarshal(payload, e)
+		if err != nil {
+			return nil, fmt.Errorf("decode xml payload: %w", err)
+		}
+		return e, nil
+	}
+	err := json.Unmarshal(payload, e)
+	if err != nil {
+		return nil, fmt.Errorf("decode json payload: %w", err)
+	}
+	return e, nil
+}
+
+type symbolizer struct {
+	buildId string
+	table   *gosym.Table
+	base    uint64
+	inliner *inliner
+}
+
+func newSymbolizer(path string) (*symbolizer, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	pclntab := file.Section(".gopclntab")
+	text := file.Section(".text")
+	if pclntab == nil || text == nil {
+		return nil, fmt.Errorf("%s has no go symbol table", path)
+	}
+	pclntabData, err := pclntab.Data()
+	if err != nil {
+		return nil, err
+	}
+	var symtabData []byte
+	symtab := file.Section(".gosymtab")
+	if symtab != nil {
+		symtabData, err = symtab.Data()
+		if err != nil {
+			return nil, err
+		}
+	}
+	table, err := gosym.NewTable(symtabData, gosym.NewLineTable(pclntabData, text.Addr))
+	if err != nil {
+		return nil, err
+	}
+	baseFunc := table.LookupFunc(errors.BaseFunc)
+	if baseFunc == nil {
+		return nil, fmt.Errorf("%s not found in %s", errors.BaseFunc, path)
+	}
+	buildId, _ := errors.ReadBuildId(path)
+	s := &symbolizer{
+		buildId: buildId,
+		table:   table,
+		base:    baseFunc.Entry,
+	}
+	data, err := file.DWARF()
+	if err == nil {
+		s.inliner = &inliner{data: data}
+	}
+	return s, nil
+}
+
+func (s *symbolizer) write(out io.Writer, raw *errors.RawStackTrace) {
+	if raw.BuildId != s.buildId {
+		_, _ = fmt.Fprintf(out, "warning: build id %q does not match binary build id %q\n", raw.BuildId, s.buildId)
+	}
+	if s.inliner == nil {
+		_, _ = fmt.Fprintln(out, "warning: binary has no DWARF data, inlined frames may be misattributed")
+	}
+	slide := uint64(raw.Base) - s.base
+	for _, pc := range raw.PCs {
+		addr := uint64(pc) - slide - 1
+		file, line, fn := s.table.PCToLine(addr)
+		if fn == nil {
+			_, _ = fmt.Fprintf(out, "%#x\n\t?\n", pc)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "%s\n\t%s:%d\n", s.function(addr, fn.Name), file, line)
+	}
+	if raw.Truncated {
+		_, _ = fmt.Fprintln(out, "...")
+	}
+}
+
+func (s *symbolizer) function(addr uint64, physical string) string {
+	if s.inliner == nil {
+		return physical
+	}
+	function, err := s.inliner.function(addr)
+	if err != nil || function == "" {
+		return physical
+	}
+	return function
+}
@@ -1,16 +1,40 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
 
 type Config struct {
-	CollectStackTrace bool
+	// Deprecated: use StackTraceMode instead.
+	CollectStackTrace       bool
+	StackTraceMode          StackTraceMode
+	StackTraceDepth         int
+	StackTraceSampleRate    float64
+	StackTraceAdaptiveLimit int
+	StackTraceWrapMode      StackTraceWrapMode
+
+	StackTraceEncoding      StackTraceEncoding
+	StackTraceFilters       []func(frame Frame) bool
+	TrimStackTracePath      func(file string) string
+	FoldStackTraceRecursion bool
+	StackTraceSourceLines   int
+
+	FingerprintCauseCodes  bool
+	FingerprintFrames      int
+	FingerprintFrameFilter func(frame Frame) bool
+
+	GenerateInstance func() string
+	Now              func() time.Time
+
+	ExtractParams []func(ctx context.Context) Params
 
 	IsPrivateParam func(name string) bool
 
@@ -19,17 +43,46 @@ type Config struct {
 	UnmarshalJsonKey   func(name string) string
 	UnmarshalJsonParam map[string]func(data []byte) (any, error)
 
+	ProblemType func(code Code) string
+
 	MarshalXMLKey     func(name string) string
 	MarshalXmlParam   map[string]func(en *xml.Encoder, start xml.StartElement, value any) error
 	UnmarshalXMLKey   func(name string) string
 	UnmarshalXmlParam map[string]func(d *xml.Decoder, start xml.StartElement) (any, error)
 
-	MarshalCause      bool
-	MarshalStackTrace bool
+	MarshalCause            bool
+	MarshalMultiCause       bool
+	MarshalStackTrace       bool
+	MarshalStackTraceSource bool
+	MarshalInstance         bool
+	MarshalTime             bool
+	MarshalFingerprint      bool
 }
 
 var cfg = Config{
-	CollectStackTrace: true,
+	CollectStackTrace:       true,
+	StackTraceMode:          StackTraceFull,
+	StackTraceDepth:         32,
+	StackTraceSampleRate:    0.01,
+	StackTraceAdaptiveLimit: 100,
+	StackTraceWrapMode:      StackTraceWrapDiff,
+
+	StackTraceEncoding:      StackTraceSymbolized,
+	StackTraceFilters:       nil,
+	TrimStackTracePath:      nil,
+	FoldStackTraceRecursion: true,
+	StackTraceSourceLines:   0,
+
+	FingerprintCauseCodes:  true,
+	FingerprintFrames:      3,
+	FingerprintFrameFilter: ApplicationFrames,
+
+	GenerateInstance: NewInstance,
+	Now:              time.Now,
+
+	ExtractParams: []func(ctx context.Context) Params{
+		ExtractContextParams,
+	},
 
 	IsPrivateParam: func(name string) bool {
 		r, _ := utf8.DecodeRuneInString(name)
@@ -61,17 +114,6 @@ var cfg = Config{
 			}
 			return json.Marshal(v)
 		},
-		keyStackTrace: func(v any) ([]byte, error) {
-			st, ok := v.(StackTrace)
-			if !ok {
-				return json.Marshal(v)
-			}
-			strs := make([]string, 0, len(st))
-			for _, frame := range st {
-				strs = append(strs, fmt.Sprintf("%s %s:%d", frame.Func(), frame.File(), frame.Line()))
-			}
-			return json.Marshal(strs)
-		},
 	},
 	UnmarshalJsonKey: func(name string) string {
 		r, n := utf8.DecodeRuneInString(name)
@@ -97,6 +139,43 @@ var cfg = Config{
 			}
 			return message, nil
 		},
+		keyInstance: func(data []byte) (any, error) {
+			var instance string
+			err := json.Unmarshal(data, &instance)
+			if err != nil {
+				return nil, err
+			}
+			return instance, nil
+		},
+		keyTime: func(data []byte) (any, error) {
+			var t time.Time
+			err := json.Unmarshal(data, &t)
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		},
+		keyLimit: func(data []byte) (any, error) {
+			var limit int64
+			err := json.Unmarshal(data, &limit)
+			if err != nil {
+				return nil, err
+			}
+			return limit, nil
+		},
+		keyStackTrace: func(data []byte) (any, error) {
+			raw := &RawStackTrace{}
+			err := json.Unmarshal(data, raw)
+			if err == nil {
+				return newRawStackTrace(raw), nil
+			}
+			var strs []string
+			err = json.Unmarshal(data, &strs)
+			if err != nil {
+				return nil, err
+			}
+			return parseStackTrace(strs), nil
+		},
 		keyCause: func(data []byte) (any, error) {
 			e := &Error{}
 			err := json.Unmarshal(data, e)
@@ -112,6 +191,10 @@ var cfg = Config{
 		},
 	},
 
+	ProblemType: func(code Code) string {
+		return problemTypeBlank
+	},
+
 	MarshalXMLKey: func(name string) string {
 		r, n := utf8.DecodeRuneInString(name)
 		if unicode.IsUpper(r) {
@@ -137,13 +220,6 @@ var cfg = Config{
 			}
 			return en.EncodeElement(v, start)
 		},
-		keyStackTrace: func(en *xml.Encoder, start xml.StartElement, v any) error {
-			st, ok := v.(StackTrace)
-			if ok {
-				return en.EncodeElement(st.String(), start)
-			}
-			return en.EncodeElement(v, start)
-		},
 		keyValidationErrors: func(en *xml.Encoder, start xml.StartElement, v any) error {
 			errs, ok := v.(map[string]string)
 			if !ok {
@@ -154,7 +230,14 @@ var cfg = Config{
 				return err
 			}
 			for key, value := range errs {
-				err = en.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: key}})
+				element := xml.StartElement{Name: xml.Name{Local: key}}
+				if !isXmlName(key) {
+					element = xml.StartElement{
+						Name: xml.Name{Local: xmlValidationField},
+						Attr: []xml.Attr{{Name: xml.Name{Local: xmlValidationFieldName}, Value: key}},
+					}
+				}
+				err = en.EncodeElement(value, element)
 				if err != nil {
 					return err
 				}
@@ -186,15 +269,64 @@ var cfg = Config{
 			}
 			return message, nil
 		},
+		keyInstance: func(d *xml.Decoder, start xml.StartElement) (any, error) {
+			var instance string
+			err := d.DecodeElement(&instance, &start)
+			if err != nil {
+				return nil, err
+			}
+			return instance, nil
+		},
+		keyTime: func(d *xml.Decoder, start xml.StartElement) (any, error) {
+			var t time.Time
+			err := d.DecodeElement(&t, &start)
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		},
+		keyLimit: func(d *xml.Decoder, start xml.StartElement) (any, error) {
+			var limit int64
+			err := d.DecodeElement(&limit, &start)
+			if err != nil {
+				return nil, err
+			}
+			return limit, nil
+		},
+		keyStackTrace: func(d *xml.Decoder, start xml.StartElement) (any, error) {
+			var value struct {
+				RawStackTrace
+				Text string `xml:",chardata"`
+			}
+			err := d.DecodeElement(&value, &start)
+			if err != nil {
+				return nil, err
+			}
+			if value.BuildId != "" || len(value.PCs) != 0 {
+				return newRawStackTrace(&value.RawStackTrace), nil
+			}
+			return parseStackTraceString(value.Text), nil
+		},
 		keyCause: func(d *xml.Decoder, start xml.StartElement) (any, error) {
+			var raw struct {
+				Inner []byte `xml:",innerxml"`
+			}
+			err := d.DecodeElement(&raw, &start)
+			if err != nil {
+				return nil, err
+			}
+			data := make([]byte, 0, len(raw.Inner)+len("<Cause></Cause>"))
+			data = append(data, "<Cause>"...)
+			data = append(data, raw.Inner...)
+			data = append(data, "</Cause>"...)
 			e := &Error{}
-			err := d.DecodeElement(e, &start)
+			err = xml.Unmarshal(data, e)
 			if err == nil {
 				return e, nil
 			}
 			var str string
-			err = d.DecodeElement(&str, &start)
-			if err != nil {
+			err = xml.Unmarshal(data, &str)
+			if err == nil {
 				return errors.New(str), nil
 			}
 			return nil, errors.New(fmt.Sprintf("unprocessable error type %s", start.Name.Local))
@@ -206,11 +338,19 @@ var cfg = Config{
 				if token == nil {
 					break
 				}
+				if _, ok := token.(xml.EndElement); ok {
+					break
+				}
 				start, ok := token.(xml.StartElement)
 				if !ok {
 					continue
 				}
 				key := start.Name.Local
+				for _, attr := range start.Attr {
+					if key == xmlValidationField && attr.Name.Local == xmlValidationFieldName {
+						key = attr.Value
+					}
+				}
 				var value string
 				err := d.DecodeElement(&value, &start)
 				if err != nil {
@@ -222,10 +362,96 @@ var cfg = Config{
 		},
 	},
 
-	MarshalCause:      false,
-	MarshalStackTrace: false,
+	MarshalCause:            false,
+	MarshalMultiCause:       false,
+	MarshalStackTrace:       false,
+	MarshalStackTraceSource: false,
+	MarshalInstance:         true,
+	MarshalTime:             true,
+	MarshalFingerprint:      false,
+}
+
+const (
+	xmlValidationField     = "Field"
+	xmlValidationFieldName = "Name"
+)
+
+func isXmlName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for index, r := range name {
+		if unicode.IsLetter(r) || r == '_' {
+			continue
+		}
+		if index != 0 && (unicode.IsDigit(r) || r == '-' || r == '.') {
+			continue
+		}
+		return false
+	}
+	return !strings.HasPrefix(strings.ToLower(name), "xml")
+}
+
+func init() {
+	cfg.MarshalJsonParam[keyStackTrace] = func(v any) ([]byte, error) {
+		st, ok := v.(*StackTrace)
+		if !ok {
+			return json.Marshal(v)
+		}
+		raw := st.Raw()
+		if raw != nil && (cfg.StackTraceEncoding == StackTraceRaw || st.raw != nil) {
+			return json.Marshal(raw)
+		}
+		frames := st.Frames()
+		strs := make([]string, 0, len(frames)+1)
+		for _, frame := range frames {
+			str := fmt.Sprintf("%s %s:%d", frame.Func(), frame.File(), frame.Line())
+			if frame.Repeated() != 0 {
+				str += " " + repeatedMarker(frame.Repeated())
+			}
+			if cfg.MarshalStackTraceSource {
+				source := frame.Source()
+				if len(source) != 0 {
+					str += "\n" + formatSource(source)
+				}
+			}
+			strs = append(strs, str)
+		}
+		if st.Truncated() {
+			strs = append(strs, truncatedMarker)
+		}
+		if st.shared != 0 {
+			strs = append(strs, fmt.Sprintf(commonMarker, st.shared))
+		}
+		return json.Marshal(strs)
+	}
+	cfg.MarshalXmlParam[keyStackTrace] = func(en *xml.Encoder, start xml.StartElement, v any) error {
+		st, ok := v.(*StackTrace)
+		if ok && st.Raw() != nil && (cfg.StackTraceEncoding == StackTraceRaw || st.raw != nil) {
+			return en.EncodeElement(st.Raw(), start)
+		}
+		if ok && cfg.MarshalStackTraceSource {
+			return en.EncodeElement(st.String(), start)
+		}
+		if ok {
+			return en.EncodeElement(st.format(0), start)
+		}
+		return en.EncodeElement(v, start)
+	}
+	cfg.MarshalJsonParam[keyRemoteStackTrace] = cfg.MarshalJsonParam[keyStackTrace]
+	cfg.UnmarshalJsonParam[keyRemoteStackTrace] = cfg.UnmarshalJsonParam[keyStackTrace]
+	cfg.MarshalXmlParam[keyRemoteStackTrace] = cfg.MarshalXmlParam[keyStackTrace]
+	cfg.UnmarshalXmlParam[keyRemoteStackTrace] = cfg.UnmarshalXmlParam[keyStackTrace]
 }
 
 func Configure(f func(*Config)) {
+	collectStackTrace := cfg.CollectStackTrace
 	f(&cfg)
+	if cfg.CollectStackTrace != collectStackTrace {
+		cfg.StackTraceMode = StackTraceOff
+		if cfg.CollectStackTrace {
+			cfg.StackTraceMode = StackTraceFull
+		}
+	}
+	cfg.CollectStackTrace = cfg.StackTraceMode != StackTraceOff
 }
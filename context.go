@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+)
+
+func NewCtx(ctx context.Context, template Template, params ...Param) error {
+	return newError(template, nil, append(extractParams(ctx), params...))
+}
+
+func WrapCtx(ctx context.Context, err error, template Template, params ...Param) error {
+	return newError(template, nonNilErrors([]error{err}), append(extractParams(ctx), params...))
+}
+
+func extractParams(ctx context.Context) Params {
+	if ctx == nil {
+		return nil
+	}
+	var params Params
+	for _, extract := range cfg.ExtractParams {
+		params = append(params, extract(ctx)...)
+	}
+	return params
+}
+
+type ctxKey int
+
+const (
+	ctxKeyRequestId ctxKey = iota
+	ctxKeyTraceId
+	ctxKeySpanId
+	ctxKeyTenant
+	ctxKeyUser
+)
+
+func ContextWithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestId, requestId)
+}
+
+func ContextWithTrace(ctx context.Context, traceId string, spanId string) context.Context {
+	ctx = context.WithValue(ctx, ctxKeyTraceId, traceId)
+	return context.WithValue(ctx, ctxKeySpanId, spanId)
+}
+
+func ContextWithTraceparent(ctx context.Context, traceparent string) context.Context {
+	traceId, spanId, ok := parseTraceparent(traceparent)
+	if !ok {
+		return ctx
+	}
+	return ContextWithTrace(ctx, traceId, spanId)
+}
+
+func parseTraceparent(traceparent string) (string, string, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	version, traceId, spanId, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || !isHex(version) {
+		return "", "", false
+	}
+	if len(traceId) != 32 || !isHex(traceId) || traceId == strings.Repeat("0", 32) {
+		return "", "", false
+	}
+	if len(spanId) != 16 || !isHex(spanId) || spanId == strings.Repeat("0", 16) {
+		return "", "", false
+	}
+	if len(flags) != 2 || !isHex(flags) {
+		return "", "", false
+	}
+	if version == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+	return traceId, spanId, true
+}
+
+func isHex(str string) bool {
+	if str != strings.ToLower(str) {
+		return false
+	}
+	_, err := hex.DecodeString(str)
+	return err == nil
+}
+
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, ctxKeyTenant, tenant)
+}
+
+func ContextWithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, ctxKeyUser, user)
+}
+
+func ExtractContextParams(ctx context.Context) Params {
+	var params Params
+	requestId, ok := ctx.Value(ctxKeyRequestId).(string)
+	if ok {
+		params = append(params, WithRequestId(requestId))
+	}
+	traceId, ok := ctx.Value(ctxKeyTraceId).(string)
+	if ok {
+		params = append(params, WithTraceId(traceId))
+	}
+	spanId, ok := ctx.Value(ctxKeySpanId).(string)
+	if ok {
+		params = append(params, WithSpanId(spanId))
+	}
+	tenant, ok := ctx.Value(ctxKeyTenant).(string)
+	if ok {
+		params = append(params, WithTenant(tenant))
+	}
+	user, ok := ctx.Value(ctxKeyUser).(string)
+	if ok {
+		params = append(params, WithUser(user))
+	}
+	return params
+}
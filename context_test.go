@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanId  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name        string
+		traceparent string
+		valid       bool
+	}{
+		{name: "valid", traceparent: "00-" + traceId + "-" + spanId + "-01", valid: true},
+		{name: "surrounding spaces", traceparent: " 00-" + traceId + "-" + spanId + "-00 ", valid: true},
+		{name: "future version with extra fields", traceparent: "01-" + traceId + "-" + spanId + "-01-extra", valid: true},
+		{name: "version 00 with extra fields", traceparent: "00-" + traceId + "-" + spanId + "-01-extra", valid: false},
+		{name: "forbidden version", traceparent: "ff-" + traceId + "-" + spanId + "-01", valid: false},
+		{name: "uppercase", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanId + "-01", valid: false},
+		{name: "zero trace id", traceparent: "00-00000000000000000000000000000000-" + spanId + "-01", valid: false},
+		{name: "zero span id", traceparent: "00-" + traceId + "-0000000000000000-01", valid: false},
+		{name: "short trace id", traceparent: "00-" + traceId[1:] + "-" + spanId + "-01", valid: false},
+		{name: "short span id", traceparent: "00-" + traceId + "-" + spanId[1:] + "-01", valid: false},
+		{name: "non-hex flags", traceparent: "00-" + traceId + "-" + spanId + "-0g", valid: false},
+		{name: "missing flags", traceparent: "00-" + traceId + "-" + spanId, valid: false},
+		{name: "empty", traceparent: "", valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualTraceId, actualSpanId, ok := parseTraceparent(test.traceparent)
+			if ok != test.valid {
+				t.Fatalf("expected valid %v, got %v", test.valid, ok)
+			}
+			if ok && (actualTraceId != traceId || actualSpanId != spanId) {
+				t.Fatalf("unexpected ids %q %q", actualTraceId, actualSpanId)
+			}
+		})
+	}
+}
+
+func TestExtractContextParams(t *testing.T) {
+	ctx := ContextWithRequestId(context.Background(), "request")
+	ctx = ContextWithTraceparent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx = ContextWithTenant(ctx, "tenant")
+	ctx = ContextWithUser(ctx, "user")
+	err := New(NotFound, ExtractContextParams(ctx)...)
+	requestId, _ := GetRequestId(err)
+	traceId, _ := GetTraceId(err)
+	spanId, _ := GetSpanId(err)
+	tenant, _ := GetTenant(err)
+	user, _ := GetUser(err)
+	if requestId != "request" || traceId != "4bf92f3577b34da6a3ce929d0e0e4736" || spanId != "00f067aa0ba902b7" || tenant != "tenant" || user != "user" {
+		t.Fatalf("unexpected params %q %q %q %q %q", requestId, traceId, spanId, tenant, user)
+	}
+}
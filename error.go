@@ -1,22 +1,34 @@
 package errors
 
+import "time"
+
 type Code string
 
 const (
-	keyCode       = "Code"
-	keyMessage    = "Message"
-	keyCause      = "Cause"
-	keyStackTrace = "StackTrace"
+	keyCode             = "Code"
+	keyMessage          = "Message"
+	keyCause            = "Cause"
+	keyCauses           = "Causes"
+	keyStackTrace       = "StackTrace"
+	keyRemoteStackTrace = "RemoteStackTrace"
+	keyInstance         = "Instance"
+	keyTime             = "Time"
+	keyFingerprint      = "Fingerprint"
 )
 
 var _ error = (*Error)(nil)
 
 type Error struct {
-	code       Code
-	message    string
-	cause      error
-	paramsMap  map[string]any
-	stackTrace StackTrace
+	code             Code
+	hierarchy        []Code
+	message          string
+	causes           []error
+	paramsMap        map[string]any
+	stackTrace       *StackTrace
+	remoteStackTrace *StackTrace
+	instance         string
+	time             time.Time
+	fingerprint      string
 }
 
 func New(template Template, params ...Param) error {
@@ -24,7 +36,38 @@ func New(template Template, params ...Param) error {
 }
 
 func Wrap(err error, template Template, params ...Param) error {
-	return newError(template, err, params)
+	return newError(template, nonNilErrors([]error{err}), params)
+}
+
+func WrapAll(errs []error, template Template, params ...Param) error {
+	return newError(template, nonNilErrors(errs), params)
+}
+
+func Join(errs ...error) error {
+	causes := nonNilErrors(errs)
+	if len(causes) == 0 {
+		return nil
+	}
+	return newError(MultiError, causes, nil)
+}
+
+func nonNilErrors(errs []error) []error {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	causes := make([]error, 0, n)
+	for _, err := range errs {
+		if err != nil {
+			causes = append(causes, err)
+		}
+	}
+	return causes
 }
 
 func Is(err error, template Template) bool {
@@ -35,23 +78,34 @@ func Is(err error, template Template) bool {
 	if !ok {
 		return false
 	}
-	return e.Code() == template.Code
+	return e.hasCode(template.Code)
 }
 
-func newError(template Template, cause error, params Params) *Error {
-	var stackTrace StackTrace
-	if cfg.CollectStackTrace {
-		stackTrace = trace(2)
+func newError(template Template, causes []error, params Params) *Error {
+	var stackTrace *StackTrace
+	causeTrace := causeStackTrace(causes)
+	switch {
+	case causeTrace == nil || cfg.StackTraceWrapMode == StackTraceWrapKeep:
+		stackTrace = captureStackTrace(template.stackTraceMode(), 2)
+	case cfg.StackTraceWrapMode == StackTraceWrapDiff:
+		stackTrace = diffStackTrace(captureStackTrace(template.stackTraceMode(), 2), causeTrace)
 	}
-	paramsMap := mergeParamMaps(template.Params.toMap(), params.toMap())
+	return newErrorWithStackTrace(template, causes, params, stackTrace)
+}
+
+func newErrorWithStackTrace(template Template, causes []error, params Params, stackTrace *StackTrace) *Error {
+	paramsMap := mergeParamMaps(template.params().toMap(), params.toMap())
 	message := template.Message(paramsMap)
 	code := template.Code
 	return &Error{
 		code:       code,
+		hierarchy:  template.hierarchy(),
 		message:    message,
-		cause:      cause,
+		causes:     causes,
 		paramsMap:  paramsMap,
 		stackTrace: stackTrace,
+		instance:   cfg.GenerateInstance(),
+		time:       cfg.Now(),
 	}
 }
 
@@ -73,16 +127,39 @@ func (e *Error) Code() Code {
 	return e.code
 }
 
+func (e *Error) Hierarchy() []Code {
+	if e.hierarchy == nil {
+		return []Code{e.code}
+	}
+	return e.hierarchy
+}
+
+func (e *Error) hasCode(code Code) bool {
+	for _, c := range e.Hierarchy() {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *Error) Error() string {
 	return e.message
 }
 
-func (e *Error) Unwrap() error {
-	return e.cause
+func (e *Error) Unwrap() []error {
+	return e.causes
 }
 
 func (e *Error) Cause() error {
-	return e.cause
+	if len(e.causes) == 0 {
+		return nil
+	}
+	return e.causes[0]
+}
+
+func (e *Error) Causes() []error {
+	return e.causes
 }
 
 func (e *Error) Get(key string) any {
@@ -92,9 +169,19 @@ func (e *Error) Get(key string) any {
 	case keyMessage:
 		return e.message
 	case keyCause:
-		return e.cause
+		return e.Cause()
+	case keyCauses:
+		return e.Causes()
 	case keyStackTrace:
 		return e.StackTrace()
+	case keyRemoteStackTrace:
+		return e.RemoteStackTrace()
+	case keyInstance:
+		return e.instance
+	case keyTime:
+		return e.time
+	case keyFingerprint:
+		return e.Fingerprint()
 	default:
 		if e.paramsMap == nil {
 			return nil
@@ -111,6 +198,24 @@ func (e *Error) Params() Params {
 	return params
 }
 
-func (e *Error) StackTrace() StackTrace {
+func (e *Error) WithParams(params ...Param) *Error {
+	copied := *e
+	copied.paramsMap = mergeParamMaps(e.paramsMap, Params(params).toMap())
+	return &copied
+}
+
+func (e *Error) StackTrace() *StackTrace {
 	return e.stackTrace
 }
+
+func (e *Error) RemoteStackTrace() *StackTrace {
+	return e.remoteStackTrace
+}
+
+func (e *Error) Instance() string {
+	return e.instance
+}
+
+func (e *Error) Time() time.Time {
+	return e.time
+}
@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"go/build"
+	"path/filepath"
+	"strings"
+)
+
+const ownPackage = "github.com/CherkashinEvgeny/goerr"
+
+func DropRuntimeFrames(frame Frame) bool {
+	return framePackage(frame) != "runtime"
+}
+
+func DropStdlibFrames(frame Frame) bool {
+	pkg := framePackage(frame)
+	if pkg == "main" {
+		return true
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return strings.Contains(first, ".")
+}
+
+func DropOwnFrames(frame Frame) bool {
+	pkg := framePackage(frame)
+	return pkg != ownPackage && !strings.HasPrefix(pkg, ownPackage+"/")
+}
+
+func KeepModuleFrames(prefixes ...string) func(frame Frame) bool {
+	return func(frame Frame) bool {
+		pkg := framePackage(frame)
+		for _, prefix := range prefixes {
+			if pkg == prefix || strings.HasPrefix(pkg, strings.TrimSuffix(prefix, "/")+"/") {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func framePackage(frame Frame) string {
+	function := frame.Func()
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:slash+1+dot]
+}
+
+func TrimPathPrefix(prefixes ...string) func(file string) string {
+	return func(file string) string {
+		for _, prefix := range prefixes {
+			prefix = filepath.ToSlash(filepath.Clean(prefix)) + "/"
+			if strings.HasPrefix(file, prefix) {
+				return file[len(prefix):]
+			}
+		}
+		return file
+	}
+}
+
+func TrimGoPath() func(file string) string {
+	var prefixes []string
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		prefixes = append(prefixes, filepath.Join(gopath, "pkg", "mod"), filepath.Join(gopath, "src"))
+	}
+	if build.Default.GOROOT != "" {
+		prefixes = append(prefixes, filepath.Join(build.Default.GOROOT, "src"))
+	}
+	return TrimPathPrefix(prefixes...)
+}
+
+func presentFrames(frames []Frame) []Frame {
+	presented := make([]Frame, 0, len(frames))
+	for _, frame := range frames {
+		if !keepFrame(frame) {
+			continue
+		}
+		if cfg.TrimStackTracePath != nil {
+			frame.file = cfg.TrimStackTracePath(frame.file)
+		}
+		if cfg.FoldStackTraceRecursion && len(presented) != 0 {
+			last := &presented[len(presented)-1]
+			if last.function == frame.function && last.file == frame.file && last.line == frame.line {
+				last.repeated++
+				continue
+			}
+		}
+		presented = append(presented, frame)
+	}
+	return presented
+}
+
+func keepFrame(frame Frame) bool {
+	for _, filter := range cfg.StackTraceFilters {
+		if !filter(frame) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+func (e *Error) Fingerprint() string {
+	if e.fingerprint != "" {
+		return e.fingerprint
+	}
+	sb := strings.Builder{}
+	sb.WriteString(string(e.code))
+	if cfg.FingerprintCauseCodes {
+		for _, code := range Codes(e)[1:] {
+			sb.WriteString("\n")
+			sb.WriteString(string(code))
+		}
+	}
+	sb.WriteString("\n")
+	for _, function := range fingerprintFunctions(e) {
+		sb.WriteString("\n")
+		sb.WriteString(function)
+	}
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+func fingerprintFunctions(e *Error) []string {
+	if cfg.FingerprintFrames <= 0 {
+		return nil
+	}
+	st := e.remoteStackTrace
+	if st == nil {
+		st = e.stackTrace
+	}
+	if st == nil || st.sampled {
+		return nil
+	}
+	functions := make([]string, 0, cfg.FingerprintFrames)
+	for _, frame := range st.Frames() {
+		if len(functions) == cfg.FingerprintFrames {
+			break
+		}
+		if cfg.FingerprintFrameFilter != nil && !cfg.FingerprintFrameFilter(frame) {
+			continue
+		}
+		functions = append(functions, frame.Func())
+	}
+	return functions
+}
+
+func ApplicationFrames(frame Frame) bool {
+	return DropStdlibFrames(frame) && DropOwnFrames(frame)
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+var _ fmt.Formatter = (*Error)(nil)
+
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.verbose())
+			return
+		}
+		_, _ = io.WriteString(s, e.message)
+	case 's':
+		_, _ = io.WriteString(s, e.message)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.message)
+	}
+}
+
+func (e *Error) verbose() string {
+	str := fmt.Sprintf("%s: %s", e.code, e.message)
+	if e.stackTrace != nil {
+		str += "\n" + e.stackTrace.ownString()
+	}
+	if e.remoteStackTrace != nil {
+		str += "\nremote stack trace:\n" + e.remoteStackTrace.String()
+	}
+	for _, cause := range e.causes {
+		str += fmt.Sprintf("\ncaused by: %+v", cause)
+	}
+	return str
+}
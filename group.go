@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+type GroupConfig struct {
+	Template    Template
+	FailFast    bool
+	CancelOn    []Template
+	Deduplicate bool
+}
+
+type Group struct {
+	config GroupConfig
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	errs   []error
+	codes  map[Code]struct{}
+}
+
+func NewGroup(ctx context.Context, config GroupConfig) (*Group, context.Context) {
+	if config.Template.Code == "" {
+		config.Template = MultiError
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{
+		config: config,
+		cancel: cancel,
+		codes:  map[Code]struct{}{},
+	}, ctx
+}
+
+func (g *Group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		err := f()
+		if err != nil {
+			g.add(err)
+		}
+	}()
+}
+
+func (g *Group) add(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.config.Deduplicate {
+		e, ok := err.(*Error)
+		if ok {
+			_, found := g.codes[e.Code()]
+			if found {
+				return
+			}
+			g.codes[e.Code()] = struct{}{}
+		}
+	}
+	g.errs = append(g.errs, err)
+	if g.shouldCancel(err) {
+		g.cancel()
+	}
+}
+
+func (g *Group) shouldCancel(err error) bool {
+	if !g.config.FailFast {
+		return false
+	}
+	if len(g.config.CancelOn) == 0 {
+		return true
+	}
+	for _, template := range g.config.CancelOn {
+		if Is(err, template) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return newError(g.config.Template, g.errs, nil)
+}
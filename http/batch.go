@@ -0,0 +1,272 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+type Batch struct {
+	Results []BatchResult
+}
+
+type BatchResult struct {
+	Index  int
+	Id     string
+	Status int
+	Error  *errors.Error
+}
+
+func (b *Batch) Add(id string, err error) {
+	result := BatchResult{
+		Index:  len(b.Results),
+		Id:     id,
+		Status: http.StatusOK,
+	}
+	if err != nil {
+		e, ok := err.(*errors.Error)
+		if !ok {
+			e = foreignError(err)
+		}
+		result.Error = e
+		result.Status = Status(e)
+	}
+	b.Results = append(b.Results, result)
+}
+
+func (b *Batch) Failed() []BatchResult {
+	var failed []BatchResult
+	for _, result := range b.Results {
+		if result.Error != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+func (b *Batch) Err() error {
+	var errs []error
+	for _, result := range b.Failed() {
+		errs = append(errs, result.Error)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Batch) Status() int {
+	if len(b.Results) == 0 {
+		return http.StatusOK
+	}
+	status := b.Results[0].Status
+	for _, result := range b.Results[1:] {
+		if result.Status != status {
+			return http.StatusMultiStatus
+		}
+	}
+	return status
+}
+
+func WriteBatch(w http.ResponseWriter, r *http.Request, b *Batch) {
+	status := b.Status()
+	contentType := Negotiate(r.Header.Get("Accept"))
+	body, err := EncodeBatch(b, contentType)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if contentType == ContentTypeProblemJson {
+		contentType = ContentTypeJson
+	}
+	writeBody(w, r, status, contentType, body)
+}
+
+func EncodeBatch(b *Batch, contentType string) ([]byte, error) {
+	switch contentType {
+	case ContentTypeProblemJson:
+		return marshalBatchJson(b, true)
+	case ContentTypeXml, ContentTypeTextXml:
+		return xml.Marshal(b)
+	case ContentTypeText:
+		return []byte(b.String()), nil
+	default:
+		return json.Marshal(b)
+	}
+}
+
+func DecodeBatch(resp *http.Response) (*Batch, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = readCloser{bytes.NewReader(body), resp.Body}
+	b, err := decodeBatch(resp.Header.Get("Content-Type"), body)
+	if err == nil {
+		return b, nil
+	}
+	decodeErr := DecodeResponse(resp)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return nil, err
+}
+
+func decodeBatch(contentType string, body []byte) (*Batch, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	b := &Batch{}
+	switch {
+	case mediaType == ContentTypeJson || strings.HasSuffix(mediaType, "+json"):
+		err = json.Unmarshal(body, b)
+	case mediaType == ContentTypeXml || mediaType == ContentTypeTextXml || strings.HasSuffix(mediaType, "+xml"):
+		err = xml.Unmarshal(body, b)
+	default:
+		err = fmt.Errorf("unsupported batch content type %q", mediaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Batch) String() string {
+	var builder strings.Builder
+	for _, result := range b.Results {
+		_, _ = fmt.Fprintf(&builder, "%d", result.Index)
+		if result.Id != "" {
+			_, _ = fmt.Fprintf(&builder, " %s", result.Id)
+		}
+		_, _ = fmt.Fprintf(&builder, ": %d", result.Status)
+		if result.Error != nil {
+			_, _ = fmt.Fprintf(&builder, " %s: %s", result.Error.Code(), result.Error.Error())
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+type batchJson struct {
+	Results []batchResultJson `json:"results"`
+}
+
+type batchResultJson struct {
+	Index  int             `json:"index"`
+	Id     string          `json:"id,omitempty"`
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+var _ json.Marshaler = (*Batch)(nil)
+
+func (b *Batch) MarshalJSON() ([]byte, error) {
+	return marshalBatchJson(b, false)
+}
+
+func marshalBatchJson(b *Batch, problem bool) ([]byte, error) {
+	data := batchJson{Results: make([]batchResultJson, 0, len(b.Results))}
+	for _, result := range b.Results {
+		item := batchResultJson{
+			Index:  result.Index,
+			Id:     result.Id,
+			Status: result.Status,
+		}
+		if result.Error != nil {
+			var err error
+			if problem {
+				item.Error, err = json.Marshal(errors.Problem{Error: result.Error, Status: result.Status})
+			} else {
+				item.Error, err = json.Marshal(result.Error)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		data.Results = append(data.Results, item)
+	}
+	return json.Marshal(data)
+}
+
+var _ json.Unmarshaler = (*Batch)(nil)
+
+func (b *Batch) UnmarshalJSON(bytes []byte) error {
+	var data struct {
+		Results *[]batchResultJson `json:"results"`
+	}
+	err := json.Unmarshal(bytes, &data)
+	if err != nil {
+		return err
+	}
+	if data.Results == nil {
+		return fmt.Errorf("batch results are missing")
+	}
+	results := make([]BatchResult, 0, len(*data.Results))
+	for _, item := range *data.Results {
+		result := BatchResult{
+			Index:  item.Index,
+			Id:     item.Id,
+			Status: item.Status,
+		}
+		if len(item.Error) != 0 && string(item.Error) != "null" {
+			p := &errors.Problem{}
+			err = json.Unmarshal(item.Error, p)
+			if err != nil {
+				return fmt.Errorf("batch result %d: %w", item.Index, err)
+			}
+			result.Error = p.Error.WithParams(WithStatus(result.Status))
+		}
+		results = append(results, result)
+	}
+	b.Results = results
+	return nil
+}
+
+type batchXml struct {
+	Results []batchResultXml `xml:"Result"`
+}
+
+type batchResultXml struct {
+	Index  int           `xml:"Index,attr"`
+	Id     string        `xml:"Id,attr,omitempty"`
+	Status int           `xml:"Status,attr"`
+	Error  *errors.Error `xml:"Error,omitempty"`
+}
+
+var _ xml.Marshaler = (*Batch)(nil)
+
+func (b *Batch) MarshalXML(en *xml.Encoder, _ xml.StartElement) error {
+	data := batchXml{Results: make([]batchResultXml, 0, len(b.Results))}
+	for _, result := range b.Results {
+		data.Results = append(data.Results, batchResultXml(result))
+	}
+	return en.EncodeElement(data, xml.StartElement{Name: xml.Name{Local: "Results"}})
+}
+
+var _ xml.Unmarshaler = (*Batch)(nil)
+
+func (b *Batch) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	data := batchXml{}
+	err := d.DecodeElement(&data, &start)
+	if err != nil {
+		return err
+	}
+	results := make([]BatchResult, 0, len(data.Results))
+	for _, item := range data.Results {
+		result := BatchResult(item)
+		if result.Error != nil {
+			result.Error = result.Error.WithParams(WithStatus(result.Status))
+		}
+		results = append(results, result)
+	}
+	b.Results = results
+	return nil
+}
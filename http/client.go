@@ -0,0 +1,95 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+const maxErrorBodySize = 1 << 20
+
+func Do(client *http.Client, r *http.Request) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	err = DecodeResponse(resp)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+func DecodeResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err == nil {
+		e, ok := decodeBody(resp.Header.Get("Content-Type"), body)
+		if ok {
+			params := append(headerParams(e, resp.Header), WithStatus(resp.StatusCode))
+			return e.WithParams(params...)
+		}
+	}
+	template := StatusTemplate(resp.StatusCode)
+	code := resp.Header.Get(HeaderErrorCode)
+	if code != "" && errors.Code(code) != template.Code {
+		parent := template
+		template = errors.Template{
+			Code:    errors.Code(code),
+			Message: parent.Message,
+			Parent:  &parent,
+		}
+	}
+	e := errors.New(template, WithStatus(resp.StatusCode)).(*errors.Error)
+	return e.WithParams(headerParams(e, resp.Header)...)
+}
+
+func decodeBody(contentType string, body []byte) (*errors.Error, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	switch {
+	case mediaType == ContentTypeProblemJson:
+		problem := &errors.Problem{}
+		err = json.Unmarshal(body, problem)
+		if err != nil {
+			return nil, false
+		}
+		return problem.Error, true
+	case mediaType == ContentTypeJson || strings.HasSuffix(mediaType, "+json"):
+		e := &errors.Error{}
+		err = json.Unmarshal(body, e)
+		if err != nil {
+			return nil, false
+		}
+		return e, true
+	case mediaType == ContentTypeXml || mediaType == ContentTypeTextXml || strings.HasSuffix(mediaType, "+xml"):
+		e := &errors.Error{}
+		err = xml.Unmarshal(body, e)
+		if err != nil {
+			return nil, false
+		}
+		return e, true
+	default:
+		return nil, false
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+const (
+	HeaderIfMatch           = "If-Match"
+	HeaderIfNoneMatch       = "If-None-Match"
+	HeaderIfUnmodifiedSince = "If-Unmodified-Since"
+)
+
+const (
+	keyExpected = "Expected"
+	keyActual   = "Actual"
+)
+
+func WithExpected(expected string) errors.Param {
+	return errors.Param{Name: keyExpected, Value: expected}
+}
+
+func GetExpected(err error) (string, bool) {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		return "", false
+	}
+	expected, ok := e.Get(keyExpected).(string)
+	return expected, ok
+}
+
+func WithActual(actual string) errors.Param {
+	return errors.Param{Name: keyActual, Value: actual}
+}
+
+func GetActual(err error) (string, bool) {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		return "", false
+	}
+	actual, ok := e.Get(keyActual).(string)
+	return actual, ok
+}
+
+func RequirePreconditions(r *http.Request, etag string, modified time.Time) error {
+	if r.Header.Get(HeaderIfMatch) == "" && r.Header.Get(HeaderIfUnmodifiedSince) == "" {
+		return errors.New(errors.PreconditionRequired, errors.WithPrecondition(HeaderIfMatch))
+	}
+	return CheckPreconditions(r, etag, modified)
+}
+
+func CheckPreconditions(r *http.Request, etag string, modified time.Time) error {
+	etag = normalizeETag(etag)
+	exists := etag != "" || !modified.IsZero()
+	ifMatch := r.Header.Get(HeaderIfMatch)
+	if ifMatch != "" {
+		if !matchETag(ifMatch, etag, exists, true) {
+			return preconditionFailed(HeaderIfMatch, ifMatch, etag)
+		}
+	} else {
+		ifUnmodifiedSince := r.Header.Get(HeaderIfUnmodifiedSince)
+		since, err := http.ParseTime(ifUnmodifiedSince)
+		if ifUnmodifiedSince != "" && err == nil && !modified.IsZero() && modified.Truncate(time.Second).After(since) {
+			return preconditionFailed(HeaderIfUnmodifiedSince, ifUnmodifiedSince, modified.UTC().Format(http.TimeFormat))
+		}
+	}
+	ifNoneMatch := r.Header.Get(HeaderIfNoneMatch)
+	if ifNoneMatch != "" && matchETag(ifNoneMatch, etag, exists, false) {
+		err := preconditionFailed(HeaderIfNoneMatch, ifNoneMatch, etag)
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return err.(*errors.Error).WithParams(WithStatus(http.StatusNotModified))
+		}
+		return err
+	}
+	return nil
+}
+
+func preconditionFailed(precondition string, expected string, actual string) error {
+	params := errors.Params{
+		errors.WithPrecondition(precondition),
+		WithExpected(expected),
+		WithActual(actual),
+	}
+	if strings.HasPrefix(actual, `"`) || strings.HasPrefix(actual, `W/"`) {
+		params = append(params, WithETag(actual))
+	}
+	return errors.New(errors.PreconditionFailed, params...)
+}
+
+func normalizeETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+func matchETag(header string, etag string, exists bool, strong bool) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return exists
+	}
+	if etag == "" {
+		return false
+	}
+	current, currentWeak := strings.CutPrefix(etag, "W/")
+	for _, candidate := range parseETags(header) {
+		candidate, candidateWeak := strings.CutPrefix(candidate, "W/")
+		if strong && (candidateWeak || currentWeak) {
+			continue
+		}
+		if candidate == current {
+			return true
+		}
+	}
+	return false
+}
+
+func parseETags(header string) []string {
+	var etags []string
+	for {
+		header = strings.TrimLeft(header, " \t,")
+		if header == "" {
+			return etags
+		}
+		prefix := ""
+		if strings.HasPrefix(header, "W/") {
+			prefix = "W/"
+			header = header[len(prefix):]
+		}
+		if !strings.HasPrefix(header, `"`) {
+			etag, rest, _ := strings.Cut(header, ",")
+			etags = append(etags, prefix+strings.TrimSpace(etag))
+			header = rest
+			continue
+		}
+		end := strings.Index(header[1:], `"`)
+		if end < 0 {
+			return append(etags, prefix+header)
+		}
+		etags = append(etags, prefix+header[:end+2])
+		header = header[end+2:]
+	}
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		etag     string
+		exists   bool
+		strong   bool
+		expected bool
+	}{
+		{name: "exact", header: `"a"`, etag: `"a"`, exists: true, strong: true, expected: true},
+		{name: "mismatch", header: `"a"`, etag: `"b"`, exists: true, strong: true, expected: false},
+		{name: "list", header: `"x", "a"`, etag: `"a"`, exists: true, strong: true, expected: true},
+		{name: "list without spaces", header: `"x","a"`, etag: `"a"`, exists: true, strong: true, expected: true},
+		{name: "comma inside etag", header: `"a,b"`, etag: `"a,b"`, exists: true, strong: true, expected: true},
+		{name: "comma inside etag does not split", header: `"a,b"`, etag: `"b"`, exists: true, strong: false, expected: false},
+		{name: "weak candidate strong comparison", header: `W/"a"`, etag: `"a"`, exists: true, strong: true, expected: false},
+		{name: "weak candidate weak comparison", header: `W/"a"`, etag: `"a"`, exists: true, strong: false, expected: true},
+		{name: "weak current strong comparison", header: `"a"`, etag: `W/"a"`, exists: true, strong: true, expected: false},
+		{name: "weak current weak comparison", header: `"a"`, etag: `W/"a"`, exists: true, strong: false, expected: true},
+		{name: "wildcard existing", header: `*`, etag: "", exists: true, strong: true, expected: true},
+		{name: "wildcard missing", header: ` * `, etag: "", exists: false, strong: true, expected: false},
+		{name: "no current etag", header: `"a"`, etag: "", exists: true, strong: false, expected: false},
+		{name: "empty list entries", header: `, ,"a"`, etag: `"a"`, exists: true, strong: true, expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := matchETag(test.header, test.etag, test.exists, test.strong)
+			if actual != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseETags(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected []string
+	}{
+		{header: ``, expected: nil},
+		{header: `"a"`, expected: []string{`"a"`}},
+		{header: `"a", W/"b"`, expected: []string{`"a"`, `W/"b"`}},
+		{header: `"a,b",W/"c d"`, expected: []string{`"a,b"`, `W/"c d"`}},
+		{header: `a, "b`, expected: []string{`a`, `"b`}},
+	}
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			actual := parseETags(test.header)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Fatalf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		value    string
+		etag     string
+		expected int
+	}{
+		{name: "if-match ok", method: http.MethodPut, header: HeaderIfMatch, value: `"v1"`, etag: "v1"},
+		{name: "if-match failed", method: http.MethodPut, header: HeaderIfMatch, value: `"v0"`, etag: "v1", expected: http.StatusPreconditionFailed},
+		{name: "if-none-match get", method: http.MethodGet, header: HeaderIfNoneMatch, value: `W/"v1"`, etag: "v1", expected: http.StatusNotModified},
+		{name: "if-none-match put", method: http.MethodPut, header: HeaderIfNoneMatch, value: `*`, etag: "v1", expected: http.StatusPreconditionFailed},
+		{name: "if-none-match ok", method: http.MethodGet, header: HeaderIfNoneMatch, value: `"v0"`, etag: "v1"},
+		{name: "if-unmodified-since ok", method: http.MethodPut, header: HeaderIfUnmodifiedSince, value: modified.Format(http.TimeFormat)},
+		{name: "if-unmodified-since failed", method: http.MethodPut, header: HeaderIfUnmodifiedSince, value: modified.Add(-time.Second).Format(http.TimeFormat), expected: http.StatusPreconditionFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, "/", nil)
+			r.Header.Set(test.header, test.value)
+			err := CheckPreconditions(r, test.etag, modified)
+			if test.expected == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errors.PreconditionFailed) || Status(err) != test.expected {
+				t.Fatalf("expected status %d, got %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+func DecodeJSON(r *http.Request, v any) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return decodeJSONError(err, nil, nil)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(v)
+	if err != nil {
+		return decodeJSONError(err, data, reflect.TypeOf(v))
+	}
+	_, err = decoder.Token()
+	if err != io.EOF {
+		return validationError(nil, "", fmt.Sprintf("unexpected data after JSON value at offset %d", decoder.InputOffset()))
+	}
+	return nil
+}
+
+func decodeJSONError(err error, data []byte, t reflect.Type) error {
+	var maxBytesErr *http.MaxBytesError
+	if stderrors.As(err, &maxBytesErr) {
+		return errors.Wrap(err, errors.PayloadTooLarge, errors.WithLimit(maxBytesErr.Limit))
+	}
+	var syntaxErr *json.SyntaxError
+	if stderrors.As(err, &syntaxErr) {
+		return validationError(err, "", fmt.Sprintf("invalid JSON at offset %d", syntaxErr.Offset))
+	}
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &typeErr) {
+		pointer, found := valuePointer(data, typeErr.Offset)
+		if !found {
+			pointer = jsonPointer(strings.Split(typeErr.Field, ".")...)
+		}
+		return validationError(err, pointer, fmt.Sprintf("expected %s, got %s at offset %d", typeErr.Type, typeErr.Value, typeErr.Offset))
+	}
+	switch {
+	case stderrors.Is(err, io.EOF):
+		return validationError(err, "", "request body is empty")
+	case stderrors.Is(err, io.ErrUnexpectedEOF):
+		return validationError(err, "", "unexpected end of JSON input")
+	}
+	field, found := unknownField(err.Error())
+	if found {
+		pointer, found := unknownFieldPointer(data, t, field)
+		if !found {
+			pointer = jsonPointer(field)
+		}
+		return validationError(err, pointer, "unknown field")
+	}
+	return validationError(err, "", err.Error())
+}
+
+func unknownField(message string) (string, bool) {
+	_, field, found := strings.Cut(message, "unknown field ")
+	if !found {
+		return "", false
+	}
+	unquoted, err := strconv.Unquote(field)
+	if err == nil {
+		return unquoted, true
+	}
+	field, _, _ = strings.Cut(field, " ")
+	return strings.Trim(field, `"`), true
+}
+
+type jsonContainer struct {
+	array     bool
+	index     int
+	key       string
+	expectKey bool
+}
+
+func valuePointer(data []byte, offset int64) (string, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var stack []*jsonContainer
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", false
+		}
+		delim, isDelim := token.(json.Delim)
+		if len(stack) != 0 {
+			top := stack[len(stack)-1]
+			if top.expectKey && !isDelim {
+				top.key, _ = token.(string)
+				top.expectKey = false
+				continue
+			}
+		}
+		if isDelim && (delim == '}' || delim == ']') {
+			if decoder.InputOffset() >= offset {
+				return containerPointer(stack[:len(stack)-1]), true
+			}
+			stack = stack[:len(stack)-1]
+			completeValue(stack)
+			continue
+		}
+		if decoder.InputOffset() >= offset {
+			return containerPointer(stack), true
+		}
+		if isDelim {
+			stack = append(stack, &jsonContainer{array: delim == '[', expectKey: delim == '{'})
+			continue
+		}
+		completeValue(stack)
+	}
+}
+
+func completeValue(stack []*jsonContainer) {
+	if len(stack) == 0 {
+		return
+	}
+	top := stack[len(stack)-1]
+	if top.array {
+		top.index++
+		return
+	}
+	top.expectKey = true
+}
+
+func containerPointer(stack []*jsonContainer) string {
+	segments := make([]string, 0, len(stack))
+	for _, container := range stack {
+		if container.array {
+			segments = append(segments, strconv.Itoa(container.index))
+			continue
+		}
+		segments = append(segments, container.key)
+	}
+	return jsonPointer(segments...)
+}
+
+func unknownFieldPointer(data []byte, t reflect.Type, field string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	path, found, err := findUnknownField(decoder, t, nil, field)
+	if err != nil || !found {
+		return "", false
+	}
+	return jsonPointer(path...), true
+}
+
+func findUnknownField(decoder *json.Decoder, t reflect.Type, path []string, field string) ([]string, bool, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, false, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil, false, nil
+	}
+	t = indirectType(t)
+	if delim == '{' {
+		for decoder.More() {
+			token, err = decoder.Token()
+			if err != nil {
+				return nil, false, err
+			}
+			key, _ := token.(string)
+			elem, known := objectElem(t, key)
+			keyPath := append(path[:len(path):len(path)], key)
+			if !known && key == field {
+				return keyPath, true, nil
+			}
+			found, ok, err := findUnknownField(decoder, elem, keyPath, field)
+			if err != nil || ok {
+				return found, ok, err
+			}
+		}
+		_, err = decoder.Token()
+		return nil, false, err
+	}
+	for index := 0; decoder.More(); index++ {
+		found, ok, err := findUnknownField(decoder, arrayElem(t), append(path[:len(path):len(path)], strconv.Itoa(index)), field)
+		if err != nil || ok {
+			return found, ok, err
+		}
+	}
+	_, err = decoder.Token()
+	return nil, false, err
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		if t.Implements(jsonUnmarshalerType) {
+			return nil
+		}
+		t = t.Elem()
+	}
+	if t != nil && reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	return t
+}
+
+func objectElem(t reflect.Type, key string) (reflect.Type, bool) {
+	if t == nil {
+		return nil, true
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem(), true
+	case reflect.Struct:
+		field, found := structField(t, key)
+		if !found {
+			return nil, false
+		}
+		return field.Type, true
+	default:
+		return nil, true
+	}
+}
+
+func arrayElem(t reflect.Type) reflect.Type {
+	if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return nil
+	}
+	return t.Elem()
+}
+
+func structField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded, found := structField(fieldType, key)
+			if found {
+				return embedded, true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func jsonPointer(segments ...string) string {
+	sb := strings.Builder{}
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		sb.WriteString("/")
+		segment = strings.ReplaceAll(segment, "~", "~0")
+		segment = strings.ReplaceAll(segment, "/", "~1")
+		sb.WriteString(segment)
+	}
+	return sb.String()
+}
+
+func DecodeXML(r *http.Request, v any) error {
+	err := xml.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return nil
+	}
+	var maxBytesErr *http.MaxBytesError
+	if stderrors.As(err, &maxBytesErr) {
+		return errors.Wrap(err, errors.PayloadTooLarge, errors.WithLimit(maxBytesErr.Limit))
+	}
+	var syntaxErr *xml.SyntaxError
+	if stderrors.As(err, &syntaxErr) {
+		return validationError(err, "", fmt.Sprintf("invalid XML at line %d: %s", syntaxErr.Line, syntaxErr.Msg))
+	}
+	if stderrors.Is(err, io.EOF) {
+		return validationError(err, "", "request body is empty")
+	}
+	return validationError(err, "", err.Error())
+}
+
+func validationError(err error, field string, message string) error {
+	return errors.Wrap(err, errors.ValidationError, errors.WithValidationErrors(map[string]string{
+		field: message,
+	}))
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+type decodeEmbedded struct {
+	Id string `json:"id"`
+}
+
+type decodeRequest struct {
+	decodeEmbedded
+	Sub struct {
+		X int `json:"x"`
+	} `json:"sub"`
+	List []struct {
+		N int `json:"n"`
+	} `json:"list"`
+	Map     map[string]struct{ A int } `json:"map"`
+	Ignored string                     `json:"-"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		pointer string
+		valid   bool
+	}{
+		{name: "valid", body: `{"id":"1","sub":{"x":1},"list":[{"n":1}],"map":{"k":{"A":1}}}`, valid: true},
+		{name: "case insensitive", body: `{"ID":"1","SUB":{"X":1}}`, valid: true},
+		{name: "unknown root field", body: `{"y":1}`, pointer: "/y"},
+		{name: "unknown nested field", body: `{"sub":{"y":1}}`, pointer: "/sub/y"},
+		{name: "unknown field in array", body: `{"list":[{"n":1},{"z":2}]}`, pointer: "/list/1/z"},
+		{name: "unknown field in map value", body: `{"map":{"k/x":{"B":1}}}`, pointer: "/map/k~1x/B"},
+		{name: "unknown field after known one", body: `{"sub":{"x":1},"list":[{"x":1}]}`, pointer: "/list/0/x"},
+		{name: "ignored field", body: `{"Ignored":"x"}`, pointer: "/Ignored"},
+		{name: "type error", body: `{"sub":{"x":"1"}}`, pointer: "/sub/x"},
+		{name: "type error in array", body: `{"list":[{"n":1},{"n":"x"}]}`, pointer: "/list/1/n"},
+		{name: "type error in map with dotted key", body: `{"map":{"a.b":{"A":"s"}}}`, pointer: "/map/a.b/A"},
+		{name: "type error with object value", body: `{"sub":{"x":{"y":1}}}`, pointer: "/sub/x"},
+		{name: "root type error", body: `[]`, pointer: ""},
+		{name: "syntax error", body: `{"sub":}`, pointer: ""},
+		{name: "unexpected end", body: `{"sub":`, pointer: ""},
+		{name: "empty body", body: ``, pointer: ""},
+		{name: "trailing data", body: `{} {}`, pointer: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			var v decodeRequest
+			err := DecodeJSON(r, &v)
+			if test.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errors.ValidationError) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			validationErrors, _ := errors.GetValidationErrors(err)
+			_, found := validationErrors[test.pointer]
+			if !found || len(validationErrors) != 1 {
+				t.Fatalf("expected pointer %q, got %v", test.pointer, validationErrors)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONTooLarge(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":"0123456789"}`))
+	r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, 4)
+	var v decodeRequest
+	err := DecodeJSON(r, &v)
+	if !errors.Is(err, errors.PayloadTooLarge) {
+		t.Fatalf("expected PayloadTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+const (
+	HeaderErrorCode       = "X-Error-Code"
+	HeaderRetryAfter      = "Retry-After"
+	HeaderWWWAuthenticate = "WWW-Authenticate"
+	HeaderAllow           = "Allow"
+	HeaderETag            = "ETag"
+)
+
+const keyHeaderPrefix = "httpHeader:"
+
+func WithHeader(name string, value string) errors.Param {
+	return errors.Param{Name: keyHeaderPrefix + http.CanonicalHeaderKey(name), Value: value}
+}
+
+func WithAuthenticate(challenge string) errors.Param {
+	return WithHeader(HeaderWWWAuthenticate, challenge)
+}
+
+func WithAllow(methods ...string) errors.Param {
+	return WithHeader(HeaderAllow, strings.Join(methods, ", "))
+}
+
+func WithETag(etag string) errors.Param {
+	return WithHeader(HeaderETag, etag)
+}
+
+const keyRetryAfter = "RetryAfter"
+
+func WithRetryAfter(retryAfter time.Duration) errors.Param {
+	return errors.Param{Name: keyRetryAfter, Value: int(retryAfter.Round(time.Second) / time.Second)}
+}
+
+func GetRetryAfter(err error) (time.Duration, bool) {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		return 0, false
+	}
+	seconds, ok := e.Get(keyRetryAfter).(int)
+	return time.Duration(seconds) * time.Second, ok
+}
+
+func Headers(err error) http.Header {
+	header := http.Header{}
+	e, ok := err.(*errors.Error)
+	if !ok {
+		return header
+	}
+	for _, param := range e.Params() {
+		name, found := strings.CutPrefix(param.Name, keyHeaderPrefix)
+		if !found {
+			continue
+		}
+		value, ok := param.Value.(string)
+		if ok {
+			header.Set(name, value)
+		}
+	}
+	retryAfter, ok := GetRetryAfter(e)
+	if ok {
+		header.Set(HeaderRetryAfter, strconv.Itoa(int(retryAfter/time.Second)))
+	}
+	header.Set(HeaderErrorCode, string(e.Code()))
+	return header
+}
+
+var responseHeaders = []string{
+	HeaderWWWAuthenticate,
+	HeaderAllow,
+	HeaderETag,
+}
+
+func headerParams(e *errors.Error, header http.Header) errors.Params {
+	var params errors.Params
+	for _, name := range responseHeaders {
+		value := header.Get(name)
+		if value != "" {
+			params = append(params, WithHeader(name, value))
+		}
+	}
+	_, found := GetRetryAfter(e)
+	if !found {
+		retryAfter, ok := parseRetryAfter(header.Get(HeaderRetryAfter))
+		if ok {
+			params = append(params, WithRetryAfter(retryAfter))
+		}
+	}
+	return params
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	retryAfter := time.Until(date)
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	return retryAfter, true
+}
+
+func init() {
+	errors.Configure(func(config *errors.Config) {
+		config.UnmarshalJsonParam[keyRetryAfter] = func(data []byte) (any, error) {
+			var seconds int
+			err := json.Unmarshal(data, &seconds)
+			if err != nil {
+				return nil, err
+			}
+			return seconds, nil
+		}
+		config.UnmarshalXmlParam[keyRetryAfter] = func(d *xml.Decoder, start xml.StartElement) (any, error) {
+			var seconds int
+			err := d.DecodeElement(&seconds, &start)
+			if err != nil {
+				return nil, err
+			}
+			return seconds, nil
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+func Normalize(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nw := &normalizingWriter{ResponseWriter: w, request: r}
+		h.ServeHTTP(nw, r)
+		nw.finish()
+	})
+}
+
+func TimeoutHandler(h http.Handler, dt time.Duration) http.Handler {
+	return Normalize(http.TimeoutHandler(h, dt, ""))
+}
+
+func MaxBytesHandler(h http.Handler, n int64) http.Handler {
+	return Normalize(http.MaxBytesHandler(h, n))
+}
+
+const (
+	maxBuiltinBodySize = 1 << 10
+	timeoutBody        = "<html><head><title>Timeout</title></head><body><h1>Timeout</h1></body></html>"
+)
+
+type normalizingWriter struct {
+	http.ResponseWriter
+	request     *http.Request
+	wroteHeader bool
+	intercepted bool
+	timeout     bool
+	status      int
+	body        bytes.Buffer
+}
+
+func (w *normalizingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status >= 400 && w.Header().Get(HeaderErrorCode) == "" {
+		w.timeout = isTimeoutHeader(status, w.Header())
+		if w.timeout || isErrorHeader(w.Header()) {
+			w.intercepted = true
+			w.status = status
+			return
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *normalizingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.intercepted {
+		return w.ResponseWriter.Write(b)
+	}
+	_, _ = w.body.Write(b)
+	if w.body.Len() > maxBuiltinBodySize {
+		err := w.release()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *normalizingWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.intercepted {
+		return
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *normalizingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *normalizingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *normalizingWriter) release() error {
+	w.intercepted = false
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(w.body.Bytes())
+	w.body.Reset()
+	return err
+}
+
+func (w *normalizingWriter) finish() {
+	if !w.intercepted {
+		return
+	}
+	if w.timeout && w.body.String() != timeoutBody {
+		_ = w.release()
+		return
+	}
+	header := w.Header()
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	header.Del("X-Content-Type-Options")
+	WriteError(w.ResponseWriter, w.request, builtinError(w.request, w.status, w.timeout, strings.TrimSpace(w.body.String())))
+}
+
+func isErrorHeader(header http.Header) bool {
+	return header.Get("Content-Type") == "text/plain; charset=utf-8" && header.Get("X-Content-Type-Options") == "nosniff"
+}
+
+func isTimeoutHeader(status int, header http.Header) bool {
+	return status == http.StatusServiceUnavailable && header.Get("Content-Type") == ""
+}
+
+func builtinError(r *http.Request, status int, timeout bool, reason string) error {
+	if timeout {
+		return errors.New(errors.Timeout, WithStatus(status))
+	}
+	var params errors.Params
+	if reason != "" {
+		params = append(params, errors.WithReason(reason))
+	}
+	switch status {
+	case http.StatusNotFound:
+		return errors.New(errors.NotFound, params...)
+	case http.StatusMethodNotAllowed:
+		return errors.New(errors.MethodNotAllowed, append(params, errors.WithMethod(r.Method))...)
+	case http.StatusRequestEntityTooLarge:
+		return errors.New(errors.PayloadTooLarge, params...)
+	default:
+		return errors.New(StatusTemplate(status), append(params, WithStatus(status))...)
+	}
+}
+
+func foreignError(err error) *errors.Error {
+	var maxBytesErr *http.MaxBytesError
+	if stderrors.As(err, &maxBytesErr) {
+		return errors.Wrap(err, errors.PayloadTooLarge, errors.WithLimit(maxBytesErr.Limit)).(*errors.Error)
+	}
+	if stderrors.Is(err, http.ErrHandlerTimeout) {
+		return errors.Wrap(err, errors.Timeout, WithStatus(http.StatusServiceUnavailable)).(*errors.Error)
+	}
+	return errors.Wrap(err, errors.InternalError).(*errors.Error)
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/http/httputil"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+const keyUpstreamHost = "upstreamHost"
+
+func WithUpstreamHost(host string) errors.Param {
+	return errors.Param{Name: keyUpstreamHost, Value: host}
+}
+
+func GetUpstreamHost(err error) (string, bool) {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		return "", false
+	}
+	host, ok := e.Get(keyUpstreamHost).(string)
+	return host, ok
+}
+
+func ConfigureProxy(proxy *httputil.ReverseProxy) {
+	modifyResponse := proxy.ModifyResponse
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		if modifyResponse != nil {
+			err := modifyResponse(resp)
+			if err != nil {
+				return err
+			}
+		}
+		return ProxyModifyResponse(resp)
+	}
+	proxy.ErrorHandler = ProxyErrorHandler
+}
+
+func ProxyModifyResponse(resp *http.Response) error {
+	if resp.StatusCode < 500 {
+		return nil
+	}
+	err := DecodeResponse(resp)
+	_ = resp.Body.Close()
+	var template errors.Template
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable:
+		template = errors.Unavailable
+	case http.StatusGatewayTimeout:
+		template = errors.GatewayTimeout
+	default:
+		template = errors.BadGateway
+	}
+	return errors.Wrap(err, template, WithUpstreamHost(upstreamHost(resp.Request)))
+}
+
+func ProxyErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		e = errors.Wrap(err, proxyTemplate(err), WithUpstreamHost(upstreamHost(r))).(*errors.Error)
+	}
+	WriteError(w, r, e)
+}
+
+func proxyTemplate(err error) errors.Template {
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return errors.GatewayTimeout
+	}
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return errors.GatewayTimeout
+	}
+	var opErr *net.OpError
+	if stderrors.As(err, &opErr) && opErr.Op == "dial" {
+		return errors.Unavailable
+	}
+	return errors.BadGateway
+}
+
+func upstreamHost(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
+	}
+	return r.URL.Host
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net/http"
+	"sync"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+var registry = struct {
+	sync.RWMutex
+	statuses  map[errors.Code]int
+	templates map[int]errors.Template
+}{
+	statuses:  map[errors.Code]int{},
+	templates: map[int]errors.Template{},
+}
+
+func Register(template errors.Template, status int) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.statuses[template.Code] = status
+	_, found := registry.templates[status]
+	if !found {
+		registry.templates[status] = template
+	}
+}
+
+func RegisterStatusTemplate(status int, template errors.Template) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.templates[status] = template
+}
+
+func lookupStatus(e *errors.Error) (int, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, code := range e.Hierarchy() {
+		status, found := registry.statuses[code]
+		if found {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+func StatusTemplate(status int) errors.Template {
+	registry.RLock()
+	template, found := registry.templates[status]
+	registry.RUnlock()
+	if found {
+		return template
+	}
+	if status >= 400 && status < 500 {
+		return errors.ValidationError
+	}
+	return errors.InternalError
+}
+
+func init() {
+	Register(errors.ValidationError, http.StatusBadRequest)
+	Register(errors.BlockingLink, http.StatusBadRequest)
+	Register(errors.ChecksumError, http.StatusBadRequest)
+	Register(errors.Unauthorized, http.StatusUnauthorized)
+	Register(errors.Forbidden, http.StatusForbidden)
+	Register(errors.NotFound, http.StatusNotFound)
+	Register(errors.MethodNotAllowed, http.StatusMethodNotAllowed)
+	Register(errors.PayloadTooLarge, http.StatusRequestEntityTooLarge)
+	Register(errors.Timeout, http.StatusRequestTimeout)
+	Register(errors.AlreadyExists, http.StatusConflict)
+	Register(errors.AlreadyInProgress, http.StatusConflict)
+	Register(errors.IllegalState, http.StatusConflict)
+	Register(errors.PreconditionFailed, http.StatusPreconditionFailed)
+	Register(errors.PreconditionRequired, http.StatusPreconditionRequired)
+	Register(errors.ToManyRequests, http.StatusTooManyRequests)
+	Register(errors.InternalError, http.StatusInternalServerError)
+	Register(errors.NotImplemented, http.StatusNotImplemented)
+	Register(errors.BadGateway, http.StatusBadGateway)
+	Register(errors.Unavailable, http.StatusServiceUnavailable)
+	Register(errors.GatewayTimeout, http.StatusGatewayTimeout)
+}
@@ -17,46 +17,44 @@ func Status(err error) int {
 	if found {
 		return status
 	}
-	switch e.Code() {
-	case errors.CodeValidationError:
-		return http.StatusBadRequest
-	case errors.CodeBlockingLink:
-		return http.StatusBadRequest
-	case errors.CodeChecksumError:
-		return http.StatusBadRequest
-	case errors.CodeUnauthorized:
-		return http.StatusUnauthorized
-	case errors.CodeForbidden:
-		return http.StatusForbidden
-	case errors.CodeNotFound:
-		return http.StatusNotFound
-	case errors.CodeTimeout:
-		return http.StatusRequestTimeout
-	case errors.CodeAlreadyExists:
-		return http.StatusConflict
-	case errors.CodeAlreadyInProgress:
-		return http.StatusConflict
-	case errors.CodeIllegalState:
-		return http.StatusConflict
-	case errors.CodePreconditionFailed:
-		return http.StatusPreconditionFailed
-	case errors.CodePreconditionRequired:
-		return http.StatusPreconditionRequired
-	case errors.CodeToManyRequests:
-		return http.StatusTooManyRequests
-	case errors.CodeInternalError:
-		return http.StatusInternalServerError
-	case errors.CodeNotImplemented:
-		return http.StatusNotImplemented
-	default:
+	if e.Code() == errors.CodeMultiError {
+		return multiStatus(e.Causes())
+	}
+	status, found = lookupStatus(e)
+	if found {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func multiStatus(errs []error) int {
+	if len(errs) == 0 {
 		return http.StatusInternalServerError
 	}
+	status := Status(errs[0])
+	clientErrors := true
+	for _, err := range errs {
+		s := Status(err)
+		if s != status {
+			status = 0
+		}
+		if s < 400 || s >= 500 {
+			clientErrors = false
+		}
+	}
+	if status != 0 {
+		return status
+	}
+	if clientErrors {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
 }
 
 const keyStatus = "httpStatus"
 
 func WithStatus(status int) errors.Param {
-	return errors.Param{keyStatus, status}
+	return errors.Param{Name: keyStatus, Value: status}
 }
 
 func GetStatus(err error) (int, bool) {
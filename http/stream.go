@@ -0,0 +1,171 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+const (
+	ContentTypeEventStream = "text/event-stream"
+	ContentTypeNdjson      = "application/x-ndjson"
+)
+
+const (
+	eventError     = "error"
+	eventMessage   = "message"
+	ndjsonErrorKey = "error"
+)
+
+func WriteEventError(w io.Writer, err error) error {
+	data, err := json.Marshal(streamError(err))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "event: "+eventError+"\ndata: "+string(data)+"\n\n")
+	if err != nil {
+		return err
+	}
+	flush(w)
+	return nil
+}
+
+func WriteNdjsonError(w io.Writer, err error) error {
+	data, err := json.Marshal(map[string]*errors.Error{ndjsonErrorKey: streamError(err)})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	if err != nil {
+		return err
+	}
+	flush(w)
+	return nil
+}
+
+func streamError(err error) *errors.Error {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		e = foreignError(err)
+	}
+	return e
+}
+
+func flush(w io.Writer) {
+	flusher, ok := w.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+type Event struct {
+	Type string
+	Id   string
+	Data string
+}
+
+type EventReader struct {
+	reader *bufio.Reader
+	lastId string
+}
+
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{reader: bufio.NewReader(r)}
+}
+
+func (r *EventReader) Next() (Event, error) {
+	event := Event{}
+	var data []string
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil {
+			return Event{}, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" {
+			if data != nil {
+				break
+			}
+			event.Type = ""
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Type = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				r.lastId = value
+			}
+		}
+	}
+	if event.Type == "" {
+		event.Type = eventMessage
+	}
+	event.Id = r.lastId
+	event.Data = strings.Join(data, "\n")
+	if event.Type != eventError {
+		return event, nil
+	}
+	e := &errors.Error{}
+	err := json.Unmarshal([]byte(event.Data), e)
+	if err != nil {
+		return event, nil
+	}
+	return Event{}, e
+}
+
+type NdjsonReader struct {
+	reader *bufio.Reader
+}
+
+func NewNdjsonReader(r io.Reader) *NdjsonReader {
+	return &NdjsonReader{reader: bufio.NewReader(r)}
+}
+
+func (r *NdjsonReader) Next() (json.RawMessage, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return nil, err
+			}
+			continue
+		}
+		e, ok := ndjsonError(line)
+		if ok {
+			return nil, e
+		}
+		return line, nil
+	}
+}
+
+func ndjsonError(line []byte) (*errors.Error, bool) {
+	record := map[string]json.RawMessage{}
+	err := json.Unmarshal(line, &record)
+	if err != nil || len(record) != 1 {
+		return nil, false
+	}
+	data, ok := record[ndjsonErrorKey]
+	if !ok {
+		return nil, false
+	}
+	e := &errors.Error{}
+	err = json.Unmarshal(data, e)
+	if err != nil {
+		return nil, false
+	}
+	return e, true
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+func TestEventReaderNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		stream   string
+		expected []Event
+	}{
+		{name: "empty", stream: ``, expected: nil},
+		{name: "single", stream: "data: a\n\n", expected: []Event{{Type: "message", Data: "a"}}},
+		{name: "multi-line data", stream: "data: a\ndata: b\n\n", expected: []Event{{Type: "message", Data: "a\nb"}}},
+		{name: "crlf", stream: "event: tick\r\ndata: x\r\n\r\n", expected: []Event{{Type: "tick", Data: "x"}}},
+		{name: "no space after colon", stream: "data:x\n\n", expected: []Event{{Type: "message", Data: "x"}}},
+		{name: "only first space stripped", stream: "data:  x\n\n", expected: []Event{{Type: "message", Data: " x"}}},
+		{name: "empty data", stream: "data\n\n", expected: []Event{{Type: "message", Data: ""}}},
+		{name: "comments ignored", stream: ": ping\ndata: a\n: ping\n\n", expected: []Event{{Type: "message", Data: "a"}}},
+		{name: "unknown fields ignored", stream: "retry: 10\nfoo: bar\ndata: a\n\n", expected: []Event{{Type: "message", Data: "a"}}},
+		{name: "id persists", stream: "id: 1\ndata: a\n\ndata: b\n\n", expected: []Event{{Type: "message", Id: "1", Data: "a"}, {Type: "message", Id: "1", Data: "b"}}},
+		{name: "event without data not dispatched", stream: "event: x\n\ndata: a\n\n", expected: []Event{{Type: "message", Data: "a"}}},
+		{name: "incomplete event discarded", stream: "data: a\n\ndata: b", expected: []Event{{Type: "message", Data: "a"}}},
+		{name: "invalid error payload", stream: "event: error\ndata: oops\n\n", expected: []Event{{Type: "error", Data: "oops"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := NewEventReader(strings.NewReader(test.stream))
+			var events []Event
+			for {
+				event, err := reader.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				events = append(events, event)
+			}
+			if !reflect.DeepEqual(events, test.expected) {
+				t.Fatalf("expected %+v, got %+v", test.expected, events)
+			}
+		})
+	}
+}
+
+func TestEventErrorRoundTrip(t *testing.T) {
+	w := httptest.NewRecorder()
+	_, _ = io.WriteString(w, "data: before\n\n")
+	err := WriteEventError(w, errors.New(errors.Timeout))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = io.WriteString(w, "data: after\n\n")
+	if !w.Flushed {
+		t.Fatalf("expected error frame to be flushed")
+	}
+	reader := NewEventReader(w.Body)
+	event, err := reader.Next()
+	if err != nil || event.Data != "before" {
+		t.Fatalf("unexpected event %+v: %v", event, err)
+	}
+	_, err = reader.Next()
+	if !errors.Is(err, errors.Timeout) {
+		t.Fatalf("expected Timeout, got %v", err)
+	}
+	event, err = reader.Next()
+	if err != nil || event.Data != "after" {
+		t.Fatalf("unexpected event %+v: %v", event, err)
+	}
+}
+
+func TestNdjsonReaderNext(t *testing.T) {
+	var buf bytes.Buffer
+	_, _ = io.WriteString(&buf, "{\"a\":1}\n\n{\"error\":\"plain\"}\n")
+	err := WriteNdjsonError(&buf, fmt.Errorf("boom"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = io.WriteString(&buf, `{"error":{"code":"X"},"id":1}`)
+	reader := NewNdjsonReader(&buf)
+	expected := []string{`{"a":1}`, `{"error":"plain"}`, "", `{"error":{"code":"X"},"id":1}`}
+	for index, record := range expected {
+		actual, err := reader.Next()
+		if record == "" {
+			if !errors.Is(err, errors.InternalError) {
+				t.Fatalf("record %d: expected InternalError, got %v", index, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("record %d: unexpected error: %v", index, err)
+		}
+		if !bytes.Equal(actual, json.RawMessage(record)) {
+			t.Fatalf("record %d: expected %s, got %s", index, record, actual)
+		}
+	}
+	_, err = reader.Next()
+	if err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
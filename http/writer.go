@@ -0,0 +1,157 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+
+	errors "github.com/CherkashinEvgeny/goerr"
+)
+
+const (
+	ContentTypeJson        = "application/json"
+	ContentTypeProblemJson = "application/problem+json"
+	ContentTypeXml         = "application/xml"
+	ContentTypeTextXml     = "text/xml"
+	ContentTypeText        = "text/plain"
+)
+
+var contentTypes = []string{
+	ContentTypeJson,
+	ContentTypeProblemJson,
+	ContentTypeXml,
+	ContentTypeText,
+	ContentTypeTextXml,
+}
+
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := f(w, r)
+	if err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		e = foreignError(err)
+	}
+	status := Status(e)
+	contentType := Negotiate(r.Header.Get("Accept"))
+	body, err := Encode(e, status, contentType)
+	if err != nil {
+		contentType = ContentTypeText
+		body = []byte(e.Error())
+	}
+	header := w.Header()
+	for name, values := range Headers(e) {
+		header[name] = values
+	}
+	writeBody(w, r, status, contentType, body)
+}
+
+func writeBody(w http.ResponseWriter, r *http.Request, status int, contentType string, body []byte) {
+	header := w.Header()
+	if !bodyAllowed(status) {
+		w.WriteHeader(status)
+		return
+	}
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	header.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+func Encode(e *errors.Error, status int, contentType string) ([]byte, error) {
+	switch contentType {
+	case ContentTypeProblemJson:
+		return json.Marshal(errors.Problem{Error: e, Status: status})
+	case ContentTypeXml, ContentTypeTextXml:
+		return xml.Marshal(e)
+	case ContentTypeText:
+		return []byte(e.Error()), nil
+	default:
+		return json.Marshal(e)
+	}
+}
+
+func Negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return ContentTypeJson
+	}
+	ranges := parseAccept(accept)
+	best := ContentTypeJson
+	bestQuality := 0.0
+	for _, contentType := range contentTypes {
+		quality := acceptQuality(ranges, contentType)
+		if quality > bestQuality {
+			best = contentType
+			bestQuality = quality
+		}
+	}
+	return best
+}
+
+type mediaRange struct {
+	typ     string
+	subtype string
+	quality float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		typ, subtype, found := strings.Cut(mediaType, "/")
+		if !found {
+			continue
+		}
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err == nil && q >= 0 && q <= 1 {
+				quality = q
+			}
+		}
+		ranges = append(ranges, mediaRange{typ, subtype, quality})
+	}
+	return ranges
+}
+
+func acceptQuality(ranges []mediaRange, contentType string) float64 {
+	typ, subtype, _ := strings.Cut(contentType, "/")
+	quality := 0.0
+	specificity := -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		}
+		if s > specificity {
+			specificity = s
+			quality = r.quality
+		}
+	}
+	return quality
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{accept: "", expected: ContentTypeJson},
+		{accept: "  ", expected: ContentTypeJson},
+		{accept: "*/*", expected: ContentTypeJson},
+		{accept: "application/xml", expected: ContentTypeXml},
+		{accept: "Application/XML", expected: ContentTypeXml},
+		{accept: "text/xml", expected: ContentTypeTextXml},
+		{accept: "text/*", expected: ContentTypeText},
+		{accept: "application/problem+json", expected: ContentTypeProblemJson},
+		{accept: "application/xml;q=0.5, application/json;q=0.9", expected: ContentTypeJson},
+		{accept: "application/json;q=0.1, application/xml", expected: ContentTypeXml},
+		{accept: "application/json;q=0, */*", expected: ContentTypeProblemJson},
+		{accept: "application/*;q=0.2, text/plain;q=0.8", expected: ContentTypeText},
+		{accept: "application/xml;q=abc", expected: ContentTypeXml},
+		{accept: "application/xml;q=2, application/json;q=0.5", expected: ContentTypeXml},
+		{accept: "image/png", expected: ContentTypeJson},
+		{accept: "invalid", expected: ContentTypeJson},
+	}
+	for _, test := range tests {
+		t.Run(test.accept, func(t *testing.T) {
+			actual := Negotiate(test.accept)
+			if actual != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseAccept(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected []mediaRange
+	}{
+		{accept: "", expected: nil},
+		{accept: "application/json", expected: []mediaRange{{"application", "json", 1}}},
+		{accept: "a/b;q=0.5, invalid, C/D", expected: []mediaRange{{"a", "b", 0.5}, {"c", "d", 1}}},
+		{accept: "a/b; charset=utf-8; Q = 0.3", expected: []mediaRange{{"a", "b", 0.3}}},
+		{accept: "a/b;q=-1", expected: []mediaRange{{"a", "b", 1}}},
+	}
+	for _, test := range tests {
+		t.Run(test.accept, func(t *testing.T) {
+			actual := parseAccept(test.accept)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
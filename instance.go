@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"crypto/rand"
+	"time"
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func NewInstance() string {
+	var id [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	_, _ = rand.Read(id[6:])
+	return encodeCrockford(id)
+}
+
+func encodeCrockford(id [16]byte) string {
+	var dst [26]byte
+	var acc uint32
+	var bits uint
+	n := len(dst) - 1
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint32(id[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			dst[n] = crockfordAlphabet[acc&0x1f]
+			n--
+			acc >>= 5
+			bits -= 5
+		}
+	}
+	if n >= 0 {
+		dst[n] = crockfordAlphabet[acc&0x1f]
+	}
+	return string(dst[:])
+}
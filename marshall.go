@@ -4,11 +4,20 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"time"
 )
 
 var _ json.Marshaler = (*Error)(nil)
 
 func (e *Error) MarshalJSON() ([]byte, error) {
+	data, err := e.marshalJsonFields()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+func (e *Error) marshalJsonFields() (map[string]json.RawMessage, error) {
 	fieldsCount := 2 + len(e.paramsMap)
 	if cfg.MarshalStackTrace {
 		fieldsCount++
@@ -32,19 +41,56 @@ func (e *Error) MarshalJSON() ([]byte, error) {
 	if err != nil {
 		return nil, keyMarshalError{keyMessage, err}
 	}
-	if cfg.MarshalCause && e.cause != nil {
-		data[cfg.MarshalJsonKey(keyCause)], err = marshalJson(keyCause, e.cause)
+	if cfg.MarshalInstance && e.instance != "" {
+		data[cfg.MarshalJsonKey(keyInstance)], err = marshalJson(keyInstance, e.instance)
+		if err != nil {
+			return nil, keyMarshalError{keyInstance, err}
+		}
+	}
+	if cfg.MarshalTime && !e.time.IsZero() {
+		data[cfg.MarshalJsonKey(keyTime)], err = marshalJson(keyTime, e.time)
+		if err != nil {
+			return nil, keyMarshalError{keyTime, err}
+		}
+	}
+	if cfg.MarshalFingerprint {
+		data[cfg.MarshalJsonKey(keyFingerprint)], err = marshalJson(keyFingerprint, e.Fingerprint())
+		if err != nil {
+			return nil, keyMarshalError{keyFingerprint, err}
+		}
+	}
+	if cfg.MarshalCause && len(e.causes) == 1 {
+		data[cfg.MarshalJsonKey(keyCause)], err = marshalJson(keyCause, e.causes[0])
 		if err != nil {
 			return nil, keyMarshalError{keyCause, err}
 		}
 	}
+	if cfg.MarshalMultiCause && len(e.causes) > 1 {
+		data[cfg.MarshalJsonKey(keyCauses)], err = marshalJsonCauses(e.causes)
+		if err != nil {
+			return nil, keyMarshalError{keyCauses, err}
+		}
+	}
 	if cfg.MarshalStackTrace && e.stackTrace != nil {
-		data[cfg.MarshalJsonKey(keyStackTrace)], err = marshalJson(keyStackTrace, e.stackTrace)
+		data[cfg.MarshalJsonKey(keyStackTrace)], err = marshalJson(keyStackTrace, e.marshalledStackTrace())
 		if err != nil {
 			return nil, keyMarshalError{keyStackTrace, err}
 		}
 	}
-	return json.Marshal(data)
+	if cfg.MarshalStackTrace && e.remoteStackTrace != nil {
+		data[cfg.MarshalJsonKey(keyRemoteStackTrace)], err = marshalJson(keyRemoteStackTrace, e.remoteStackTrace)
+		if err != nil {
+			return nil, keyMarshalError{keyRemoteStackTrace, err}
+		}
+	}
+	return data, nil
+}
+
+func (e *Error) marshalledStackTrace() *StackTrace {
+	if cfg.MarshalCause && len(e.causes) == 1 {
+		return e.stackTrace.own()
+	}
+	return e.stackTrace
 }
 
 func marshalJson(key string, value any) ([]byte, error) {
@@ -59,6 +105,18 @@ func marshalJson(key string, value any) ([]byte, error) {
 	return jsonValue, nil
 }
 
+func marshalJsonCauses(causes []error) ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(causes))
+	for _, cause := range causes {
+		item, err := marshalJson(keyCause, cause)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return json.Marshal(items)
+}
+
 var _ json.Unmarshaler = (*Error)(nil)
 
 func (e *Error) UnmarshalJSON(bytes []byte) error {
@@ -67,8 +125,12 @@ func (e *Error) UnmarshalJSON(bytes []byte) error {
 	if err != nil {
 		return err
 	}
+	return e.unmarshalJsonFields(data)
+}
+
+func (e *Error) unmarshalJsonFields(data map[string]json.RawMessage) error {
 	codeJson, ok := data[cfg.MarshalJsonKey(keyCode)]
-	delete(data, keyCode)
+	delete(data, cfg.MarshalJsonKey(keyCode))
 	if !ok {
 		return keyMissingError{keyCode}
 	}
@@ -81,7 +143,7 @@ func (e *Error) UnmarshalJSON(bytes []byte) error {
 		return keyCastError{keyCode}
 	}
 	messageJson, ok := data[cfg.MarshalJsonKey(keyMessage)]
-	delete(data, keyMessage)
+	delete(data, cfg.MarshalJsonKey(keyMessage))
 	if !ok {
 		return keyMissingError{keyMessage}
 	}
@@ -93,20 +155,78 @@ func (e *Error) UnmarshalJSON(bytes []byte) error {
 	if !ok {
 		return keyCastError{keyMessage}
 	}
+	instance := ""
+	instanceJson, ok := data[cfg.MarshalJsonKey(keyInstance)]
+	if ok {
+		delete(data, cfg.MarshalJsonKey(keyInstance))
+		instanceValue, err := unmarshalJson(keyInstance, instanceJson)
+		if err != nil {
+			return keyUnmarshalError{keyInstance, err}
+		}
+		instance, ok = instanceValue.(string)
+		if !ok {
+			return keyCastError{keyInstance}
+		}
+	} else {
+		instance = cfg.GenerateInstance()
+	}
+	var t time.Time
+	timeJson, ok := data[cfg.MarshalJsonKey(keyTime)]
+	if ok {
+		delete(data, cfg.MarshalJsonKey(keyTime))
+		timeValue, err := unmarshalJson(keyTime, timeJson)
+		if err != nil {
+			return keyUnmarshalError{keyTime, err}
+		}
+		t, ok = timeValue.(time.Time)
+		if !ok {
+			return keyCastError{keyTime}
+		}
+	} else {
+		t = cfg.Now()
+	}
+	var causes []error
 	causeJson, ok := data[cfg.MarshalJsonKey(keyCause)]
-	var cause error
 	if ok {
-		delete(data, keyCause)
-		causeValue, err := unmarshalJson(keyCause, causeJson)
+		delete(data, cfg.MarshalJsonKey(keyCause))
+		cause, err := unmarshalJsonCause(causeJson)
+		if err != nil {
+			return err
+		}
+		causes = append(causes, cause)
+	}
+	causesJson, ok := data[cfg.MarshalJsonKey(keyCauses)]
+	if ok {
+		delete(data, cfg.MarshalJsonKey(keyCauses))
+		var items []json.RawMessage
+		err = json.Unmarshal(causesJson, &items)
 		if err != nil {
-			return keyUnmarshalError{keyCause, err}
+			return keyUnmarshalError{keyCauses, err}
 		}
-		cause, ok = causeValue.(error)
+		for _, item := range items {
+			cause, err := unmarshalJsonCause(item)
+			if err != nil {
+				return err
+			}
+			causes = append(causes, cause)
+		}
+	}
+	var remoteStackTrace *StackTrace
+	for _, key := range []string{keyStackTrace, keyRemoteStackTrace} {
+		stackTraceJson, ok := data[cfg.MarshalJsonKey(key)]
+		if !ok {
+			continue
+		}
+		delete(data, cfg.MarshalJsonKey(key))
+		stackTraceValue, err := unmarshalJson(key, stackTraceJson)
+		if err != nil {
+			return keyUnmarshalError{key, err}
+		}
+		remoteStackTrace, ok = stackTraceValue.(*StackTrace)
 		if !ok {
-			return keyCastError{keyCause}
+			return keyCastError{key}
 		}
 	}
-	delete(data, cfg.MarshalJsonKey(keyStackTrace))
 
 	paramsMap := make(map[string]any, len(data))
 	for jsonKey, jsonValue := range data {
@@ -117,18 +237,21 @@ func (e *Error) UnmarshalJSON(bytes []byte) error {
 		}
 		paramsMap[key] = value
 	}
+	fingerprint, _ := paramsMap[keyFingerprint].(string)
+	delete(paramsMap, keyFingerprint)
 
-	var stackTrace StackTrace
-	if cfg.CollectStackTrace {
-		stackTrace = trace(1)
-	}
+	stackTrace := captureStackTrace(StackTraceInherit, 2)
 
 	*e = Error{
-		code:       code,
-		message:    message,
-		cause:      cause,
-		paramsMap:  paramsMap,
-		stackTrace: stackTrace,
+		code:             code,
+		message:          message,
+		causes:           causes,
+		paramsMap:        paramsMap,
+		stackTrace:       stackTrace,
+		remoteStackTrace: remoteStackTrace,
+		instance:         instance,
+		time:             t,
+		fingerprint:      fingerprint,
 	}
 	return nil
 }
@@ -146,6 +269,18 @@ func unmarshalJson(key string, data []byte) (any, error) {
 	return value, nil
 }
 
+func unmarshalJsonCause(data []byte) (error, error) {
+	causeValue, err := unmarshalJson(keyCause, data)
+	if err != nil {
+		return nil, keyUnmarshalError{keyCause, err}
+	}
+	cause, ok := causeValue.(error)
+	if !ok {
+		return nil, keyCastError{keyCause}
+	}
+	return cause, nil
+}
+
 var _ xml.Marshaler = (*Error)(nil)
 
 func (e *Error) MarshalXML(en *xml.Encoder, start xml.StartElement) error {
@@ -161,20 +296,53 @@ func (e *Error) MarshalXML(en *xml.Encoder, start xml.StartElement) error {
 	if err != nil {
 		return keyMarshalError{keyMessage, err}
 	}
-	if cfg.MarshalCause && e.cause != nil {
-		err = marshalXml(keyCause, en, e.cause)
+	if cfg.MarshalInstance && e.instance != "" {
+		err = marshalXml(keyInstance, en, e.instance)
+		if err != nil {
+			return keyMarshalError{keyInstance, err}
+		}
+	}
+	if cfg.MarshalTime && !e.time.IsZero() {
+		err = marshalXml(keyTime, en, e.time)
+		if err != nil {
+			return keyMarshalError{keyTime, err}
+		}
+	}
+	if cfg.MarshalFingerprint {
+		err = marshalXml(keyFingerprint, en, e.Fingerprint())
+		if err != nil {
+			return keyMarshalError{keyFingerprint, err}
+		}
+	}
+	if cfg.MarshalCause && len(e.causes) == 1 {
+		err = marshalXml(keyCause, en, e.causes[0])
 		if err != nil {
 			return keyMarshalError{keyCause, err}
 		}
 	}
+	if cfg.MarshalMultiCause && len(e.causes) > 1 {
+		err = marshalXmlCauses(en, e.causes)
+		if err != nil {
+			return keyMarshalError{keyCauses, err}
+		}
+	}
 	if cfg.MarshalStackTrace && e.stackTrace != nil {
-		err = marshalXml(keyStackTrace, en, e.stackTrace)
+		err = marshalXml(keyStackTrace, en, e.marshalledStackTrace())
 		if err != nil {
 			return keyMarshalError{keyStackTrace, err}
 		}
 	}
+	if cfg.MarshalStackTrace && e.remoteStackTrace != nil {
+		err = marshalXml(keyRemoteStackTrace, en, e.remoteStackTrace)
+		if err != nil {
+			return keyMarshalError{keyRemoteStackTrace, err}
+		}
+	}
 
 	for key, value := range e.paramsMap {
+		if cfg.IsPrivateParam(key) {
+			continue
+		}
 		err = marshalXml(key, en, value)
 		if err != nil {
 			return keyMarshalError{key, err}
@@ -191,6 +359,21 @@ func marshalXml(key string, en *xml.Encoder, value any) error {
 	return en.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: cfg.MarshalXMLKey(key)}})
 }
 
+func marshalXmlCauses(en *xml.Encoder, causes []error) error {
+	start := xml.StartElement{Name: xml.Name{Local: cfg.MarshalXMLKey(keyCauses)}}
+	err := en.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	for _, cause := range causes {
+		err = marshalXml(keyCause, en, cause)
+		if err != nil {
+			return err
+		}
+	}
+	return en.EncodeToken(start.End())
+}
+
 var _ xml.Unmarshaler = (*Error)(nil)
 
 func (e *Error) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
@@ -198,13 +381,21 @@ func (e *Error) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 	var codeFound bool
 	var message string
 	var messageFound bool
-	var cause error
+	var instance string
+	var instanceFound bool
+	var t time.Time
+	var timeFound bool
+	var causes []error
+	var remoteStackTrace *StackTrace
 	var paramsMap = map[string]any{}
 	for {
 		token, _ := d.Token()
 		if token == nil {
 			break
 		}
+		if _, ok := token.(xml.EndElement); ok {
+			break
+		}
 		start, ok := token.(xml.StartElement)
 		if !ok {
 			continue
@@ -231,17 +422,50 @@ func (e *Error) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			if !ok {
 				return keyCastError{keyMessage}
 			}
+		case keyInstance:
+			instanceFound = true
+			instanceValue, err := unmarshalXml(keyInstance, d, start)
+			if err != nil {
+				return keyUnmarshalError{keyInstance, err}
+			}
+			instance, ok = instanceValue.(string)
+			if !ok {
+				return keyCastError{keyInstance}
+			}
+		case keyTime:
+			timeFound = true
+			timeValue, err := unmarshalXml(keyTime, d, start)
+			if err != nil {
+				return keyUnmarshalError{keyTime, err}
+			}
+			t, ok = timeValue.(time.Time)
+			if !ok {
+				return keyCastError{keyTime}
+			}
 		case keyCause:
-			causeValue, err := unmarshalXml(keyCause, d, start)
+			cause, err := unmarshalXmlCause(d, start)
+			if err != nil {
+				return err
+			}
+			causes = append(causes, cause)
+		case keyCauses:
+			items, err := unmarshalXmlCauses(d)
 			if err != nil {
-				return keyUnmarshalError{keyCause, err}
+				return err
 			}
-			cause, ok = causeValue.(error)
+			causes = append(causes, items...)
+		case keyStackTrace, keyRemoteStackTrace:
+			stackTraceValue, err := unmarshalXml(key, d, start)
+			if err != nil {
+				return keyUnmarshalError{key, err}
+			}
+			st, ok := stackTraceValue.(*StackTrace)
 			if !ok {
-				return keyCastError{keyCause}
+				return keyCastError{key}
+			}
+			if key == keyRemoteStackTrace || remoteStackTrace == nil {
+				remoteStackTrace = st
 			}
-		case keyStackTrace:
-			break
 		default:
 			value, err := unmarshalXml(key, d, start)
 			if err != nil {
@@ -256,18 +480,27 @@ func (e *Error) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 	if !messageFound {
 		return keyMissingError{keyMessage}
 	}
-
-	var stackTrace StackTrace
-	if cfg.CollectStackTrace {
-		stackTrace = trace(1)
+	fingerprint, _ := paramsMap[keyFingerprint].(string)
+	delete(paramsMap, keyFingerprint)
+	if !instanceFound {
+		instance = cfg.GenerateInstance()
 	}
+	if !timeFound {
+		t = cfg.Now()
+	}
+
+	stackTrace := captureStackTrace(StackTraceInherit, 1)
 
 	*e = Error{
-		code:       code,
-		message:    message,
-		cause:      cause,
-		paramsMap:  paramsMap,
-		stackTrace: stackTrace,
+		code:             code,
+		message:          message,
+		causes:           causes,
+		paramsMap:        paramsMap,
+		stackTrace:       stackTrace,
+		remoteStackTrace: remoteStackTrace,
+		instance:         instance,
+		time:             t,
+		fingerprint:      fingerprint,
 	}
 	return nil
 }
@@ -285,6 +518,40 @@ func unmarshalXml(key string, d *xml.Decoder, start xml.StartElement) (any, erro
 	return value, nil
 }
 
+func unmarshalXmlCause(d *xml.Decoder, start xml.StartElement) (error, error) {
+	causeValue, err := unmarshalXml(keyCause, d, start)
+	if err != nil {
+		return nil, keyUnmarshalError{keyCause, err}
+	}
+	cause, ok := causeValue.(error)
+	if !ok {
+		return nil, keyCastError{keyCause}
+	}
+	return cause, nil
+}
+
+func unmarshalXmlCauses(d *xml.Decoder) ([]error, error) {
+	var causes []error
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return nil, keyUnmarshalError{keyCauses, err}
+		}
+		if _, ok := token.(xml.EndElement); ok {
+			return causes, nil
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		cause, err := unmarshalXmlCause(d, start)
+		if err != nil {
+			return nil, err
+		}
+		causes = append(causes, cause)
+	}
+}
+
 type keyMarshalError struct {
 	key string
 	err error
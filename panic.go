@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	keyPanic     = "panic"
+	keyGoroutine = "goroutine"
+)
+
+func Recover(v any) error {
+	if v == nil {
+		return nil
+	}
+	return recovered(v, 1)
+}
+
+func RecoverTo(err *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	*err = recovered(v, 1)
+}
+
+func Go(f func() error) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			errs <- err
+			close(errs)
+		}()
+		defer RecoverTo(&err)
+		err = f()
+	}()
+	return errs
+}
+
+func recovered(v any, skip int) error {
+	var causes []error
+	cause, ok := v.(error)
+	if ok {
+		causes = []error{cause}
+	}
+	params := Params{
+		{Name: keyPanic, Value: fmt.Sprint(v)},
+		{Name: keyGoroutine, Value: goroutineId()},
+	}
+	return newErrorWithStackTrace(InternalError, causes, params, panicStackTrace(skip+1))
+}
+
+func panicStackTrace(skip int) *StackTrace {
+	if cfg.StackTraceMode == StackTraceOff || cfg.StackTraceDepth <= 0 {
+		return nil
+	}
+	st := trace(skip+1, cfg.StackTraceDepth+maxPanicDepth)
+	if st == nil {
+		return nil
+	}
+	pcs := st.pcs
+	for index, pc := range pcs {
+		if funcName(pc) != "runtime.gopanic" {
+			continue
+		}
+		pcs = pcs[index+1:]
+		for len(pcs) != 0 && strings.HasPrefix(funcName(pcs[0]), "runtime.") {
+			pcs = pcs[1:]
+		}
+		break
+	}
+	truncated := st.truncated
+	if len(pcs) > cfg.StackTraceDepth {
+		pcs = pcs[:cfg.StackTraceDepth]
+		truncated = true
+	}
+	return &StackTrace{
+		pcs:       pcs,
+		truncated: truncated,
+	}
+}
+
+const maxPanicDepth = 16
+
+func funcName(pc uintptr) string {
+	fn := runtime.FuncForPC(pc - 1)
+	if fn == nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+func goroutineId() int {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := bytes.Fields(bytes.TrimPrefix(buf[:n], []byte("goroutine ")))
+	if len(fields) == 0 {
+		return 0
+	}
+	id, err := strconv.Atoi(string(fields[0]))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func GetPanic(err error) (string, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return "", false
+	}
+	panicValue, ok := e.Get(keyPanic).(string)
+	return panicValue, ok
+}
+
+func GetGoroutine(err error) (int, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return 0, false
+	}
+	goroutine, ok := e.Get(keyGoroutine).(int)
+	return goroutine, ok
+}
@@ -89,3 +89,108 @@ func GetPrecondition(err error) (string, bool) {
 	precondition, ok := e.Get(keyPrecondition).(string)
 	return precondition, ok
 }
+
+const keyRequestId = "RequestId"
+
+func WithRequestId(requestId string) Param {
+	return Param{keyRequestId, requestId}
+}
+
+func GetRequestId(err error) (string, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return "", false
+	}
+	requestId, ok := e.Get(keyRequestId).(string)
+	return requestId, ok
+}
+
+const keyTraceId = "TraceId"
+
+func WithTraceId(traceId string) Param {
+	return Param{keyTraceId, traceId}
+}
+
+func GetTraceId(err error) (string, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return "", false
+	}
+	traceId, ok := e.Get(keyTraceId).(string)
+	return traceId, ok
+}
+
+const keySpanId = "SpanId"
+
+func WithSpanId(spanId string) Param {
+	return Param{keySpanId, spanId}
+}
+
+func GetSpanId(err error) (string, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return "", false
+	}
+	spanId, ok := e.Get(keySpanId).(string)
+	return spanId, ok
+}
+
+const keyTenant = "tenant"
+
+func WithTenant(tenant string) Param {
+	return Param{keyTenant, tenant}
+}
+
+func GetTenant(err error) (string, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return "", false
+	}
+	tenant, ok := e.Get(keyTenant).(string)
+	return tenant, ok
+}
+
+const keyUser = "user"
+
+func WithUser(user string) Param {
+	return Param{keyUser, user}
+}
+
+func GetUser(err error) (string, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return "", false
+	}
+	user, ok := e.Get(keyUser).(string)
+	return user, ok
+}
+
+const keyMethod = "Method"
+
+func WithMethod(method string) Param {
+	return Param{keyMethod, method}
+}
+
+func GetMethod(err error) (string, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return "", false
+	}
+	method, ok := e.Get(keyMethod).(string)
+	return method, ok
+}
+
+const keyLimit = "Limit"
+
+func WithLimit(limit int64) Param {
+	return Param{keyLimit, limit}
+}
+
+func GetLimit(err error) (int64, bool) {
+	e, ok := err.(*Error)
+	if !ok {
+		return 0, false
+	}
+	limit, ok := e.Get(keyLimit).(int64)
+	return limit, ok
+}
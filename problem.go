@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	problemType     = "type"
+	problemTitle    = "title"
+	problemStatus   = "status"
+	problemDetail   = "detail"
+	problemInstance = "instance"
+)
+
+const problemTypeBlank = "about:blank"
+
+type Problem struct {
+	Error  *Error
+	Status int
+}
+
+var _ json.Marshaler = Problem{}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	data := map[string]json.RawMessage{}
+	problemTypeValue := problemTypeBlank
+	title := ""
+	if p.Error != nil {
+		var err error
+		data, err = p.Error.marshalJsonFields()
+		if err != nil {
+			return nil, err
+		}
+		data[problemDetail] = data[cfg.MarshalJsonKey(keyMessage)]
+		delete(data, cfg.MarshalJsonKey(keyMessage))
+		instance, ok := data[cfg.MarshalJsonKey(keyInstance)]
+		if ok {
+			delete(data, cfg.MarshalJsonKey(keyInstance))
+			data[problemInstance] = instance
+		}
+		problemTypeValue = cfg.ProblemType(p.Error.code)
+		title = string(p.Error.code)
+	}
+	if problemTypeValue == problemTypeBlank && p.Status != 0 {
+		title = http.StatusText(p.Status)
+	}
+	var err error
+	data[problemType], err = json.Marshal(problemTypeValue)
+	if err != nil {
+		return nil, keyMarshalError{problemType, err}
+	}
+	if title != "" {
+		data[problemTitle], err = json.Marshal(title)
+		if err != nil {
+			return nil, keyMarshalError{problemTitle, err}
+		}
+	}
+	if p.Status != 0 {
+		data[problemStatus], err = json.Marshal(p.Status)
+		if err != nil {
+			return nil, keyMarshalError{problemStatus, err}
+		}
+	}
+	return json.Marshal(data)
+}
+
+var _ json.Unmarshaler = (*Problem)(nil)
+
+func (p *Problem) UnmarshalJSON(bytes []byte) error {
+	data := map[string]json.RawMessage{}
+	err := json.Unmarshal(bytes, &data)
+	if err != nil {
+		return err
+	}
+	status := 0
+	statusJson, ok := data[problemStatus]
+	if ok {
+		delete(data, problemStatus)
+		err = json.Unmarshal(statusJson, &status)
+		if err != nil {
+			return keyUnmarshalError{problemStatus, err}
+		}
+	}
+	title, ok := data[problemTitle]
+	delete(data, problemTitle)
+	delete(data, problemType)
+	_, found := data[cfg.MarshalJsonKey(keyCode)]
+	if !found && ok {
+		data[cfg.MarshalJsonKey(keyCode)] = title
+	}
+	detail, ok := data[problemDetail]
+	if ok {
+		delete(data, problemDetail)
+		data[cfg.MarshalJsonKey(keyMessage)] = detail
+	}
+	instance, ok := data[problemInstance]
+	if ok {
+		delete(data, problemInstance)
+		data[cfg.MarshalJsonKey(keyInstance)] = instance
+	}
+	e := &Error{}
+	err = e.unmarshalJsonFields(data)
+	if err != nil {
+		return err
+	}
+	*p = Problem{
+		Error:  e,
+		Status: status,
+	}
+	return nil
+}
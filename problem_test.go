@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProblemMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		problem     Problem
+		problemType string
+		title       string
+	}{
+		{name: "blank type uses status text", problem: Problem{Error: New(NotFound).(*Error), Status: 404}, problemType: "about:blank", title: "Not Found"},
+		{name: "blank type without status uses code", problem: Problem{Error: New(NotFound).(*Error)}, problemType: "about:blank", title: "NotFound"},
+		{name: "nil error", problem: Problem{Status: 500}, problemType: "about:blank", title: "Internal Server Error"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.problem)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var fields struct {
+				Type  string `json:"type"`
+				Title string `json:"title"`
+			}
+			err = json.Unmarshal(data, &fields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fields.Type != test.problemType || fields.Title != test.title {
+				t.Fatalf("expected type %q and title %q, got %s", test.problemType, test.title, data)
+			}
+		})
+	}
+}
+
+func TestProblemRoundTrip(t *testing.T) {
+	data, err := json.Marshal(Problem{Error: New(NotFound, WithResource("User")).(*Error), Status: 404})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	problem := &Problem{}
+	err = json.Unmarshal(data, problem)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.Status != 404 || problem.Error.Code() != CodeNotFound || problem.Error.Error() != "User not found" {
+		t.Fatalf("unexpected problem: %+v", problem)
+	}
+}
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+type StackTraceEncoding int
+
+const (
+	StackTraceSymbolized StackTraceEncoding = iota
+	StackTraceRaw
+)
+
+const BaseFunc = ownPackage + ".trace"
+
+type RawStackTrace struct {
+	BuildId   string    `json:"buildId" xml:"BuildId"`
+	Base      uintptr   `json:"base" xml:"Base"`
+	PCs       []uintptr `json:"pcs" xml:"PC"`
+	Truncated bool      `json:"truncated,omitempty" xml:"Truncated,omitempty"`
+}
+
+func (s *StackTrace) Raw() *RawStackTrace {
+	if s == nil {
+		return nil
+	}
+	if s.raw != nil {
+		return s.raw
+	}
+	pcs := s.PCs()
+	if len(pcs) == 0 {
+		return nil
+	}
+	return &RawStackTrace{
+		BuildId:   BuildId(),
+		Base:      base(),
+		PCs:       pcs,
+		Truncated: s.Truncated(),
+	}
+}
+
+func newRawStackTrace(raw *RawStackTrace) *StackTrace {
+	frames := make([]Frame, 0, len(raw.PCs))
+	for _, pc := range raw.PCs {
+		frames = append(frames, Frame{function: fmt.Sprintf("%#x", pc)})
+	}
+	return &StackTrace{
+		truncated: raw.Truncated,
+		frames:    frames,
+		raw:       raw,
+	}
+}
+
+func base() uintptr {
+	fn := runtime.FuncForPC(reflect.ValueOf(trace).Pointer())
+	if fn == nil {
+		return 0
+	}
+	return fn.Entry()
+}
+
+var buildId struct {
+	once  sync.Once
+	value string
+}
+
+func BuildId() string {
+	buildId.once.Do(func() {
+		path, err := os.Executable()
+		if err != nil {
+			return
+		}
+		buildId.value, _ = ReadBuildId(path)
+	})
+	return buildId.value
+}
+
+func ReadBuildId(path string) (string, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	section := file.Section(".note.go.buildid")
+	if section == nil {
+		return "", fmt.Errorf("build id note not found in %s", path)
+	}
+	data, err := section.Data()
+	if err != nil {
+		return "", err
+	}
+	return parseBuildIdNote(data, file.ByteOrder)
+}
+
+func parseBuildIdNote(data []byte, order binary.ByteOrder) (string, error) {
+	if len(data) < 16 {
+		return "", fmt.Errorf("build id note is too short")
+	}
+	nameSize := order.Uint32(data[0:4])
+	descSize := order.Uint32(data[4:8])
+	name := data[12:]
+	nameEnd := 12 + int(align4(nameSize))
+	if len(data) < nameEnd+int(descSize) || !bytes.HasPrefix(name, []byte("Go\x00")) {
+		return "", fmt.Errorf("unexpected build id note")
+	}
+	return string(data[nameEnd : nameEnd+int(descSize)]), nil
+}
+
+func align4(n uint32) uint32 {
+	return (n + 3) &^ 3
+}
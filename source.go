@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+type SourceLine struct {
+	Number  int
+	Text    string
+	Current bool
+}
+
+var sourceCache sync.Map
+
+func (f Frame) Source() []SourceLine {
+	return frameSource(f, cfg.StackTraceSourceLines)
+}
+
+func frameSource(f Frame, context int) []SourceLine {
+	if context <= 0 || f.path == "" || f.line <= 0 {
+		return nil
+	}
+	lines := readSource(f.path)
+	if f.line > len(lines) {
+		return nil
+	}
+	from := f.line - context
+	if from < 1 {
+		from = 1
+	}
+	to := f.line + context
+	if to > len(lines) {
+		to = len(lines)
+	}
+	source := make([]SourceLine, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		source = append(source, SourceLine{
+			Number:  number,
+			Text:    lines[number-1],
+			Current: number == f.line,
+		})
+	}
+	return source
+}
+
+func readSource(file string) []string {
+	cached, found := sourceCache.Load(file)
+	if found {
+		return cached.([]string)
+	}
+	var lines []string
+	f, err := os.Open(file)
+	if err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		_ = f.Close()
+	}
+	sourceCache.Store(file, lines)
+	return lines
+}
+
+func formatSource(source []SourceLine) string {
+	sb := strings.Builder{}
+	for index, line := range source {
+		if index != 0 {
+			sb.WriteString("\n")
+		}
+		marker := " "
+		if line.Current {
+			marker = ">"
+		}
+		sb.WriteString(fmt.Sprintf("\t\t%s%5d | %s", marker, line.Number, line.Text))
+	}
+	return sb.String()
+}
@@ -1,28 +1,225 @@
 package errors
 
 import (
+	"fmt"
+	"math/rand"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-const maxDepth = 32
+const (
+	truncatedMarker = "..."
+	commonMarker    = "... %d frames in common with cause"
+)
+
+type StackTraceMode int
+
+const (
+	StackTraceInherit StackTraceMode = iota
+	StackTraceOff
+	StackTraceCaller
+	StackTraceFull
+	StackTraceSampled
+	StackTraceAdaptive
+)
 
-func trace(skip int) StackTrace {
-	var pcs [maxDepth]uintptr
-	n := runtime.Callers(skip+2, pcs[:])
-	st := make([]Frame, 0, n)
-	for i := 0; i < n; i++ {
-		st = append(st, Frame(pcs[i]))
+func captureStackTrace(mode StackTraceMode, skip int) *StackTrace {
+	if cfg.StackTraceMode == StackTraceOff {
+		return nil
+	}
+	if mode == StackTraceInherit {
+		mode = cfg.StackTraceMode
+	}
+	switch mode {
+	case StackTraceCaller:
+		return caller(skip + 1)
+	case StackTraceFull:
+		return trace(skip+1, cfg.StackTraceDepth)
+	case StackTraceSampled:
+		if rand.Float64() < cfg.StackTraceSampleRate {
+			return sampled(trace(skip+1, cfg.StackTraceDepth))
+		}
+		return sampled(caller(skip + 1))
+	case StackTraceAdaptive:
+		if adaptiveLimiter.allow(cfg.StackTraceAdaptiveLimit) {
+			return sampled(trace(skip+1, cfg.StackTraceDepth))
+		}
+		return sampled(caller(skip + 1))
+	default:
+		return nil
+	}
+}
+
+func sampled(st *StackTrace) *StackTrace {
+	if st != nil {
+		st.sampled = true
 	}
 	return st
 }
 
-type StackTrace []Frame
+var adaptiveLimiter = &limiter{}
+
+type limiter struct {
+	second atomic.Int64
+	count  atomic.Int64
+}
+
+func (l *limiter) allow(limit int) bool {
+	now := time.Now().Unix()
+	second := l.second.Load()
+	if second != now && l.second.CompareAndSwap(second, now) {
+		l.count.Store(0)
+	}
+	return l.count.Add(1) <= int64(limit)
+}
+
+func trace(skip int, depth int) *StackTrace {
+	if depth <= 0 {
+		return nil
+	}
+	pcs := make([]uintptr, depth+1)
+	n := runtime.Callers(skip+2, pcs)
+	truncated := n > depth
+	if truncated {
+		n = depth
+	}
+	return &StackTrace{
+		pcs:       pcs[:n:n],
+		truncated: truncated,
+	}
+}
+
+func caller(skip int) *StackTrace {
+	st := trace(skip+1, 1)
+	st.truncated = false
+	return st
+}
+
+var framesCache sync.Map
+
+func symbolize(pcs []uintptr) []Frame {
+	frames := make([]Frame, 0, len(pcs))
+	for _, pc := range pcs {
+		frames = append(frames, symbolizePC(pc)...)
+	}
+	return frames
+}
+
+func symbolizePC(pc uintptr) []Frame {
+	cached, found := framesCache.Load(pc)
+	if found {
+		return cached.([]Frame)
+	}
+	var frames []Frame
+	callersFrames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, Frame{
+			function: frame.Function,
+			file:     frame.File,
+			path:     frame.File,
+			line:     frame.Line,
+		})
+		if !more {
+			break
+		}
+	}
+	framesCache.Store(pc, frames)
+	return frames
+}
 
-func (s StackTrace) String() string {
+func NewStackTrace(frames []Frame, truncated bool) *StackTrace {
+	return &StackTrace{
+		truncated: truncated,
+		frames:    frames,
+	}
+}
+
+type StackTrace struct {
+	pcs        []uintptr
+	truncated  bool
+	sampled    bool
+	shared     int
+	common     *StackTrace
+	commonFrom int
+	raw        *RawStackTrace
+	once       sync.Once
+	frames     []Frame
+}
+
+func (s *StackTrace) PCs() []uintptr {
+	if s == nil {
+		return nil
+	}
+	if s.common == nil {
+		return s.pcs
+	}
+	commonPCs := s.common.PCs()[s.commonFrom:]
+	pcs := make([]uintptr, 0, len(s.pcs)+len(commonPCs))
+	pcs = append(pcs, s.pcs...)
+	return append(pcs, commonPCs...)
+}
+
+func (s *StackTrace) Frames() []Frame {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		if s.pcs == nil && s.common == nil {
+			return
+		}
+		s.frames = presentFrames(symbolize(s.PCs()))
+	})
+	return s.frames
+}
+
+func (s *StackTrace) Truncated() bool {
+	if s == nil {
+		return false
+	}
+	if s.common != nil {
+		return s.common.Truncated()
+	}
+	return s.truncated
+}
+
+func (s *StackTrace) String() string {
+	return s.format(cfg.StackTraceSourceLines)
+}
+
+func (s *StackTrace) format(sourceLines int) string {
+	str := formatFrames(s.Frames(), s.Truncated(), sourceLines)
+	if s != nil && s.shared != 0 {
+		if str != "" {
+			str += "\n"
+		}
+		str += fmt.Sprintf(commonMarker, s.shared)
+	}
+	return str
+}
+
+func (s *StackTrace) own() *StackTrace {
+	if s == nil || s.common == nil {
+		return s
+	}
+	return &StackTrace{
+		pcs:     s.pcs,
+		sampled: s.sampled,
+		shared:  len(s.common.PCs()) - s.commonFrom,
+	}
+}
+
+func (s *StackTrace) ownString() string {
+	return s.own().String()
+}
+
+func formatFrames(frames []Frame, truncated bool, sourceLines int) string {
 	sb := strings.Builder{}
-	for index, frame := range s {
+	for index, frame := range frames {
 		if index != 0 {
 			sb.WriteString("\n")
 		}
@@ -31,38 +228,191 @@ func (s StackTrace) String() string {
 		sb.WriteString(frame.File())
 		sb.WriteString(":")
 		sb.WriteString(strconv.Itoa(frame.Line()))
+		if frame.Repeated() != 0 {
+			sb.WriteString("\n\t")
+			sb.WriteString(repeatedMarker(frame.Repeated()))
+		}
+		source := frameSource(frame, sourceLines)
+		if len(source) != 0 {
+			sb.WriteString("\n")
+			sb.WriteString(formatSource(source))
+		}
+	}
+	if truncated {
+		if len(frames) != 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(truncatedMarker)
 	}
 	return sb.String()
 }
 
-type Frame uintptr
+type StackTraceWrapMode int
 
-func (f Frame) Func() string {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return ""
+const (
+	StackTraceWrapKeep StackTraceWrapMode = iota
+	StackTraceWrapSkip
+	StackTraceWrapDiff
+)
+
+func causeStackTrace(causes []error) *StackTrace {
+	if len(causes) != 1 {
+		return nil
+	}
+	e, ok := causes[0].(*Error)
+	if !ok {
+		return nil
 	}
-	return fn.Name()
+	return e.stackTrace
 }
 
-func (f Frame) File() string {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return ""
+func diffStackTrace(st *StackTrace, cause *StackTrace) *StackTrace {
+	if st == nil || cause == nil {
+		return st
+	}
+	causePCs := cause.PCs()
+	common := 0
+	for common < len(st.pcs) && common < len(causePCs) {
+		if st.pcs[len(st.pcs)-1-common] != causePCs[len(causePCs)-1-common] {
+			break
+		}
+		common++
+	}
+	if common == 0 {
+		return st
+	}
+	own := len(st.pcs) - common
+	return &StackTrace{
+		pcs:        st.pcs[:own:own],
+		truncated:  st.truncated,
+		sampled:    st.sampled,
+		common:     cause,
+		commonFrom: len(causePCs) - common,
 	}
-	file, _ := fn.FileLine(f.pc())
-	return file
 }
 
-func (f Frame) Line() int {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
+func repeatedMarker(repeated int) string {
+	return fmt.Sprintf("... repeated %d more times", repeated)
+}
+
+func parseStackTrace(strs []string) *StackTrace {
+	frames := make([]Frame, 0, len(strs))
+	truncated := false
+	shared := 0
+	for _, str := range strs {
+		if str == truncatedMarker {
+			truncated = true
+			continue
+		}
+		count, ok := parseCommonMarker(str)
+		if ok {
+			shared = count
+			continue
+		}
+		frames = append(frames, parseFrame(str))
+	}
+	st := NewStackTrace(frames, truncated)
+	st.shared = shared
+	return st
+}
+
+func parseCommonMarker(str string) (int, bool) {
+	var count int
+	_, err := fmt.Sscanf(str, commonMarker, &count)
+	return count, err == nil && fmt.Sprintf(commonMarker, count) == str
+}
+
+func parseFrame(str string) Frame {
+	str, _, _ = strings.Cut(str, "\n")
+	frame := Frame{}
+	index := strings.LastIndex(str, " ... repeated ")
+	if index >= 0 {
+		frame.repeated = parseRepeated(str[index+1:])
+		str = str[:index]
+	}
+	function, location, found := strings.Cut(str, " ")
+	frame.function = function
+	if found {
+		frame.file, frame.line = parseLocation(location)
+	}
+	return frame
+}
+
+func parseStackTraceString(str string) *StackTrace {
+	var frames []Frame
+	truncated := false
+	shared := 0
+	for _, line := range strings.Split(str, "\n") {
+		count, isCommon := parseCommonMarker(line)
+		switch {
+		case line == truncatedMarker:
+			truncated = true
+		case isCommon:
+			shared = count
+		case strings.HasPrefix(line, "\t\t"):
+			continue
+		case strings.HasPrefix(line, "\t... repeated ") && len(frames) != 0:
+			frames[len(frames)-1].repeated = parseRepeated(line[1:])
+		case strings.HasPrefix(line, "\t") && len(frames) != 0:
+			frames[len(frames)-1].file, frames[len(frames)-1].line = parseLocation(line[1:])
+		case line != "":
+			frames = append(frames, Frame{function: line})
+		}
+	}
+	st := NewStackTrace(frames, truncated)
+	st.shared = shared
+	return st
+}
+
+func parseLocation(location string) (string, int) {
+	index := strings.LastIndex(location, ":")
+	if index < 0 {
+		return location, 0
+	}
+	line, err := strconv.Atoi(location[index+1:])
+	if err != nil {
+		return location, 0
+	}
+	return location[:index], line
+}
+
+func parseRepeated(str string) int {
+	var repeated int
+	_, err := fmt.Sscanf(str, "... repeated %d more times", &repeated)
+	if err != nil {
 		return 0
 	}
-	_, line := fn.FileLine(f.pc())
-	return line
+	return repeated
+}
+
+func NewFrame(function string, file string, line int) Frame {
+	return Frame{
+		function: function,
+		file:     file,
+		line:     line,
+	}
+}
+
+type Frame struct {
+	function string
+	file     string
+	path     string
+	line     int
+	repeated int
+}
+
+func (f Frame) Func() string {
+	return f.function
+}
+
+func (f Frame) File() string {
+	return f.file
+}
+
+func (f Frame) Line() int {
+	return f.line
 }
 
-func (f Frame) pc() uintptr {
-	return uintptr(f) - 1
+func (f Frame) Repeated() int {
+	return f.repeated
 }
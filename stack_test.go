@@ -0,0 +1,40 @@
+package errors
+
+import "testing"
+
+var benchmarkTemplate = Template{
+	Code:    "Benchmark",
+	Message: Message("Benchmark error"),
+}
+
+var benchmarkSink string
+
+func benchmarkNew(b *testing.B, mode StackTraceMode, print bool) {
+	previous := cfg.StackTraceMode
+	Configure(func(config *Config) {
+		config.StackTraceMode = mode
+	})
+	defer Configure(func(config *Config) {
+		config.StackTraceMode = previous
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := New(benchmarkTemplate).(*Error)
+		if print {
+			benchmarkSink = err.StackTrace().String()
+		}
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.Run("collected", func(b *testing.B) {
+		benchmarkNew(b, StackTraceFull, false)
+	})
+	b.Run("off", func(b *testing.B) {
+		benchmarkNew(b, StackTraceOff, false)
+	})
+	b.Run("printed", func(b *testing.B) {
+		benchmarkNew(b, StackTraceFull, true)
+	})
+}
@@ -6,9 +6,38 @@ import (
 )
 
 type Template struct {
-	Code    Code
-	Message func(params map[string]any) string
-	Params  Params
+	Code           Code
+	Message        func(params map[string]any) string
+	Params         Params
+	StackTraceMode StackTraceMode
+	Parent         *Template
+}
+
+func (t Template) hierarchy() []Code {
+	var codes []Code
+	for template := &t; template != nil; template = template.Parent {
+		codes = append(codes, template.Code)
+	}
+	return codes
+}
+
+func (t Template) stackTraceMode() StackTraceMode {
+	for template := &t; template != nil; template = template.Parent {
+		if template.StackTraceMode != StackTraceInherit {
+			return template.StackTraceMode
+		}
+	}
+	return StackTraceInherit
+}
+
+func (t Template) params() Params {
+	if t.Parent == nil {
+		return t.Params
+	}
+	parentParams := t.Parent.params()
+	params := make(Params, 0, len(parentParams)+len(t.Params))
+	params = append(params, parentParams...)
+	return append(params, t.Params...)
 }
 
 func Message(str string) func(params map[string]any) string {